@@ -0,0 +1,482 @@
+package nanogui
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"io"
+	"strings"
+)
+
+// KeyChord is a key pressed together with modifier keys; the zero value means no shortcut
+type KeyChord struct {
+	Key       glfw.Key
+	Modifiers glfw.ModifierKey
+}
+
+// chordModifiers are the modifiers a chord can hold (the lock keys are ignored)
+const chordModifiers = glfw.ModShift | glfw.ModControl | glfw.ModAlt | glfw.ModSuper
+
+// Valid() returns whether the chord describes a key
+func (c KeyChord) Valid() bool {
+	return c.Key > 0
+}
+
+// String() returns the chord in the form used by ParseKeyChord() and shown by menus (e.g. "Ctrl+Shift+S")
+func (c KeyChord) String() string {
+	if !c.Valid() {
+		return ""
+	}
+	var parts []string
+	if c.Modifiers&glfw.ModControl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if c.Modifiers&glfw.ModAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if c.Modifiers&glfw.ModShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if c.Modifiers&glfw.ModSuper != 0 {
+		parts = append(parts, "Super")
+	}
+	name, ok := keyNames[c.Key]
+	if !ok {
+		name = fmt.Sprintf("Key%d", int(c.Key))
+	}
+	return strings.Join(append(parts, name), "+")
+}
+
+// ParseKeyChord() parses a chord like "Ctrl+Shift+S"; an empty string gives the zero chord
+func ParseKeyChord(s string) (KeyChord, error) {
+	var chord KeyChord
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return chord, nil
+	}
+	parts := strings.Split(s, "+")
+	for _, part := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "ctrl", "control":
+			chord.Modifiers |= glfw.ModControl
+		case "alt", "option":
+			chord.Modifiers |= glfw.ModAlt
+		case "shift":
+			chord.Modifiers |= glfw.ModShift
+		case "super", "cmd", "meta":
+			chord.Modifiers |= glfw.ModSuper
+		default:
+			return KeyChord{}, fmt.Errorf("ParseKeyChord: unknown modifier %q in %q", part, s)
+		}
+	}
+	name := strings.TrimSpace(parts[len(parts)-1])
+	for key, keyName := range keyNames {
+		if strings.EqualFold(name, keyName) {
+			chord.Key = key
+			return chord, nil
+		}
+	}
+	var code int
+	if _, err := fmt.Sscanf(name, "Key%d", &code); err == nil && code > 0 {
+		chord.Key = glfw.Key(code)
+		return chord, nil
+	}
+	return KeyChord{}, fmt.Errorf("ParseKeyChord: unknown key %q in %q", name, s)
+}
+
+var keyNames = make(map[glfw.Key]string)
+
+func init() {
+	keyNames[glfw.KeySpace] = "Space"
+	keyNames[glfw.KeyApostrophe] = "'"
+	keyNames[glfw.KeyComma] = ","
+	keyNames[glfw.KeyMinus] = "-"
+	keyNames[glfw.KeyPeriod] = "."
+	keyNames[glfw.KeySlash] = "/"
+	keyNames[glfw.KeySemicolon] = ";"
+	keyNames[glfw.KeyEqual] = "="
+	keyNames[glfw.KeyLeftBracket] = "["
+	keyNames[glfw.KeyBackslash] = "\\"
+	keyNames[glfw.KeyRightBracket] = "]"
+	keyNames[glfw.KeyGraveAccent] = "`"
+	keyNames[glfw.KeyEscape] = "Escape"
+	keyNames[glfw.KeyEnter] = "Enter"
+	keyNames[glfw.KeyTab] = "Tab"
+	keyNames[glfw.KeyBackspace] = "Backspace"
+	keyNames[glfw.KeyInsert] = "Insert"
+	keyNames[glfw.KeyDelete] = "Delete"
+	keyNames[glfw.KeyRight] = "Right"
+	keyNames[glfw.KeyLeft] = "Left"
+	keyNames[glfw.KeyDown] = "Down"
+	keyNames[glfw.KeyUp] = "Up"
+	keyNames[glfw.KeyPageUp] = "PageUp"
+	keyNames[glfw.KeyPageDown] = "PageDown"
+	keyNames[glfw.KeyHome] = "Home"
+	keyNames[glfw.KeyEnd] = "End"
+	keyNames[glfw.KeyPrintScreen] = "PrintScreen"
+	keyNames[glfw.KeyPause] = "Pause"
+	keyNames[glfw.KeyKPDecimal] = "NumDecimal"
+	keyNames[glfw.KeyKPDivide] = "NumDivide"
+	keyNames[glfw.KeyKPMultiply] = "NumMultiply"
+	keyNames[glfw.KeyKPSubtract] = "NumSubtract"
+	keyNames[glfw.KeyKPAdd] = "NumAdd"
+	keyNames[glfw.KeyKPEnter] = "NumEnter"
+	keyNames[glfw.KeyKPEqual] = "NumEqual"
+	keyNames[glfw.KeyMenu] = "Menu"
+	for key := glfw.KeyA; key <= glfw.KeyZ; key++ {
+		keyNames[key] = string(rune('A' + key - glfw.KeyA))
+	}
+	for key := glfw.Key0; key <= glfw.Key9; key++ {
+		keyNames[key] = string(rune('0' + key - glfw.Key0))
+	}
+	for key := glfw.KeyKP0; key <= glfw.KeyKP9; key++ {
+		keyNames[key] = fmt.Sprintf("Num%d", key-glfw.KeyKP0)
+	}
+	for key := glfw.KeyF1; key <= glfw.KeyF25; key++ {
+		keyNames[key] = fmt.Sprintf("F%d", key-glfw.KeyF1+1)
+	}
+}
+
+// ActionScope tells where the shortcut of an action works
+type ActionScope int
+
+const (
+	// ActionScopeScreen: anywhere in the screen (or in any screen)
+	ActionScopeScreen ActionScope = iota
+	// ActionScopeWindow: while the focus is inside a window
+	ActionScopeWindow
+	// ActionScopeWidget: while the focus is on a widget or inside it
+	ActionScopeWidget
+	ActionScopeCount
+)
+
+func (s ActionScope) String() string {
+	switch s {
+	case ActionScopeScreen:
+		return "Screen"
+	case ActionScopeWindow:
+		return "Window"
+	case ActionScopeWidget:
+		return "Widget"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// Action is a command of the application that can be triggered with a keyboard shortcut
+type Action struct {
+	id           string
+	label        string
+	icon         Icon
+	defaultChord KeyChord
+	chord        KeyChord
+	enabled      bool
+	scope        ActionScope
+	context      Widget
+	callback     func()
+	registry     *ActionRegistry
+}
+
+func (a *Action) ID() string {
+	return a.id
+}
+
+func (a *Action) Label() string {
+	return a.label
+}
+
+func (a *Action) SetLabel(label string) {
+	a.label = label
+}
+
+func (a *Action) Icon() Icon {
+	return a.icon
+}
+
+func (a *Action) SetIcon(icon Icon) {
+	a.icon = icon
+}
+
+// DefaultChord() returns the shortcut the action was registered with
+func (a *Action) DefaultChord() KeyChord {
+	return a.defaultChord
+}
+
+// Chord() returns the shortcut of the action
+func (a *Action) Chord() KeyChord {
+	return a.chord
+}
+
+func (a *Action) Enabled() bool {
+	return a.enabled
+}
+
+// SetEnabled() sets whether the action can be triggered
+func (a *Action) SetEnabled(enabled bool) {
+	a.enabled = enabled
+}
+
+// Scope() returns where the shortcut works, and the screen, window or widget it is bound to
+func (a *Action) Scope() (ActionScope, Widget) {
+	return a.scope, a.context
+}
+
+// SetScope() sets where the shortcut works: the context is the *Window (ActionScopeWindow) or the widget
+// (ActionScopeWidget) that must contain the focus, or the *Screen (ActionScopeScreen, nil means any screen)
+func (a *Action) SetScope(scope ActionScope, context Widget) {
+	checkActionScope(scope, context)
+	a.scope = scope
+	a.context = context
+	if a.registry != nil {
+		a.registry.notifyConflicts(a)
+	}
+}
+
+func checkActionScope(scope ActionScope, context Widget) {
+	switch scope {
+	case ActionScopeScreen:
+		if _, ok := context.(*Screen); context != nil && !ok {
+			panic("invalid action scope: the context of ActionScopeScreen must be a Screen or nil")
+		}
+	case ActionScopeWindow:
+		if _, ok := context.(IWindow); !ok {
+			panic("invalid action scope: the context of ActionScopeWindow must be a window")
+		}
+	case ActionScopeWidget:
+		if context == nil {
+			panic("invalid action scope: ActionScopeWidget needs a context widget")
+		}
+	default:
+		panic("invalid action scope")
+	}
+}
+
+// SetCallback() sets the function performing the action
+func (a *Action) SetCallback(callback func()) {
+	a.callback = callback
+}
+
+// Trigger() performs the action if it is enabled, and returns whether it was performed
+func (a *Action) Trigger() bool {
+	if !a.enabled || a.callback == nil {
+		return false
+	}
+	a.callback()
+	return true
+}
+
+// rank() returns how specific the action is for the focus of the screen (lower is more specific), or -1 if the shortcut doesn't work there
+func (a *Action) rank(screen *Screen) int {
+	if a.scope == ActionScopeScreen {
+		if a.context != nil && a.context != Widget(screen) {
+			return -1
+		}
+		return len(screen.focusPath)
+	}
+	for i, widget := range screen.focusPath {
+		if widget == a.context {
+			return i
+		}
+	}
+	return -1
+}
+
+// overlaps() returns whether the shortcuts of two actions work in the same place with the same specificity
+func (a *Action) overlaps(other *Action) bool {
+	if a.scope != other.scope {
+		return false
+	}
+	if a.scope == ActionScopeScreen {
+		return a.context == nil || other.context == nil || a.context == other.context
+	}
+	return a.context == other.context
+}
+
+// ActionRegistry holds the actions of the application and dispatches their shortcuts
+//
+// The screens look for the shortcut of a key press in the registry. Each
+// scope is dispatched either before the focused widgets get the key (see
+// SetDispatchBeforeFocus()) or, by default, after none of them used it. When
+// several actions share a shortcut, the one bound to the innermost widget of
+// the focus wins; two actions with the same shortcut in the same scope
+// conflict, and the conflict callback is invoked when it happens.
+//
+// The user can remap the shortcuts with SetChord(); SaveBindings() and
+// LoadBindings() store the remapped shortcuts.
+type ActionRegistry struct {
+	actions          []*Action
+	byID             map[string]*Action
+	bindings         map[string]KeyChord
+	beforeFocus      [ActionScopeCount]bool
+	conflictCallback func(action *Action, conflicts []*Action)
+}
+
+var actions = &ActionRegistry{
+	byID:     make(map[string]*Action),
+	bindings: make(map[string]KeyChord),
+}
+
+// Actions() returns the action registry shared by all the screens
+func Actions() *ActionRegistry {
+	return actions
+}
+
+// Register() adds an enabled action working in any screen; a shortcut loaded with LoadBindings() overrides the default chord
+func (r *ActionRegistry) Register(id, label string, chord KeyChord, callback func()) *Action {
+	return r.RegisterScoped(id, label, chord, ActionScopeScreen, nil, callback)
+}
+
+// RegisterScoped() adds an enabled action working in the given scope (see Action.SetScope())
+func (r *ActionRegistry) RegisterScoped(id, label string, chord KeyChord, scope ActionScope, context Widget, callback func()) *Action {
+	if _, ok := r.byID[id]; ok {
+		panic(fmt.Sprintf("ActionRegistry.Register: the action %q is already registered", id))
+	}
+	checkActionScope(scope, context)
+	action := &Action{
+		id:           id,
+		label:        label,
+		defaultChord: chord,
+		chord:        chord,
+		enabled:      true,
+		scope:        scope,
+		context:      context,
+		callback:     callback,
+		registry:     r,
+	}
+	if binding, ok := r.bindings[id]; ok {
+		action.chord = binding
+	}
+	r.actions = append(r.actions, action)
+	r.byID[id] = action
+	r.notifyConflicts(action)
+	return action
+}
+
+// Unregister() removes an action
+func (r *ActionRegistry) Unregister(id string) {
+	action, ok := r.byID[id]
+	if !ok {
+		return
+	}
+	delete(r.byID, id)
+	action.registry = nil
+	for i, a := range r.actions {
+		if a == action {
+			r.actions = append(r.actions[:i], r.actions[i+1:]...)
+			break
+		}
+	}
+}
+
+// Action() returns the action registered with the id, or nil
+func (r *ActionRegistry) Action(id string) *Action {
+	return r.byID[id]
+}
+
+func (r *ActionRegistry) Actions() []*Action {
+	return r.actions
+}
+
+// DispatchBeforeFocus() returns whether the shortcuts of a scope are dispatched before the focused widgets get the key
+func (r *ActionRegistry) DispatchBeforeFocus(scope ActionScope) bool {
+	return r.beforeFocus[scope]
+}
+
+// SetDispatchBeforeFocus() sets whether the shortcuts of a scope are dispatched before the focused widgets get the key
+func (r *ActionRegistry) SetDispatchBeforeFocus(scope ActionScope, before bool) {
+	r.beforeFocus[scope] = before
+}
+
+// SetConflictCallback() sets the callback invoked when an action gets a shortcut already used in its scope
+func (r *ActionRegistry) SetConflictCallback(callback func(action *Action, conflicts []*Action)) {
+	r.conflictCallback = callback
+}
+
+// Conflicts() returns the other actions using the shortcut of the action in the same scope
+func (r *ActionRegistry) Conflicts(action *Action) []*Action {
+	if !action.chord.Valid() {
+		return nil
+	}
+	var conflicts []*Action
+	for _, a := range r.actions {
+		if a != action && a.chord == action.chord && a.overlaps(action) {
+			conflicts = append(conflicts, a)
+		}
+	}
+	return conflicts
+}
+
+// SetChord() remaps the shortcut of an action (the zero chord removes it), and returns the actions it conflicts with
+func (r *ActionRegistry) SetChord(action *Action, chord KeyChord) []*Action {
+	action.chord = chord
+	if chord == action.defaultChord {
+		delete(r.bindings, action.id)
+	} else {
+		r.bindings[action.id] = chord
+	}
+	return r.notifyConflicts(action)
+}
+
+// ResetChords() gives back their default shortcut to all the actions
+func (r *ActionRegistry) ResetChords() {
+	for _, action := range r.actions {
+		action.chord = action.defaultChord
+	}
+	r.bindings = make(map[string]KeyChord)
+}
+
+func (r *ActionRegistry) notifyConflicts(action *Action) []*Action {
+	conflicts := r.Conflicts(action)
+	if len(conflicts) > 0 && r.conflictCallback != nil {
+		r.conflictCallback(action, conflicts)
+	}
+	return conflicts
+}
+
+// SaveBindings() writes the remapped shortcuts as a JSON object mapping action ids to chords
+func (r *ActionRegistry) SaveBindings(w io.Writer) error {
+	bindings := make(map[string]string, len(r.bindings))
+	for id, chord := range r.bindings {
+		bindings[id] = chord.String()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bindings)
+}
+
+// LoadBindings() reads shortcuts written by SaveBindings(); they apply to the registered actions and to the ones registered later
+func (r *ActionRegistry) LoadBindings(reader io.Reader) error {
+	var bindings map[string]string
+	if err := json.NewDecoder(reader).Decode(&bindings); err != nil {
+		return err
+	}
+	for id, text := range bindings {
+		chord, err := ParseKeyChord(text)
+		if err != nil {
+			return err
+		}
+		if action, ok := r.byID[id]; ok {
+			r.SetChord(action, chord)
+		} else {
+			r.bindings[id] = chord
+		}
+	}
+	return nil
+}
+
+// dispatch triggers the most specific enabled action of the screen focus having the shortcut of the key;
+// only the scopes dispatched before (or after) the focused widgets are considered
+func (r *ActionRegistry) dispatch(screen *Screen, key glfw.Key, modifiers glfw.ModifierKey, beforeFocus bool) bool {
+	chord := KeyChord{Key: key, Modifiers: modifiers & chordModifiers}
+	var found *Action
+	foundRank := 0
+	for _, action := range r.actions {
+		if action.chord != chord || !action.enabled || action.callback == nil || r.beforeFocus[action.scope] != beforeFocus {
+			continue
+		}
+		if rank := action.rank(screen); rank >= 0 && (found == nil || rank < foundRank) {
+			found, foundRank = action, rank
+		}
+	}
+	return found != nil && found.Trigger()
+}
@@ -0,0 +1,251 @@
+package nanogui
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"testing"
+)
+
+func newTestRegistry() *ActionRegistry {
+	return &ActionRegistry{
+		byID:     make(map[string]*Action),
+		bindings: make(map[string]KeyChord),
+	}
+}
+
+func TestKeyChordString(t *testing.T) {
+	tests := []struct {
+		chord KeyChord
+		want  string
+	}{
+		{KeyChord{}, ""},
+		{KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModControl}, "Ctrl+S"},
+		{KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModShift | glfw.ModControl}, "Ctrl+Shift+S"},
+		{KeyChord{Key: glfw.KeyF5}, "F5"},
+		{KeyChord{Key: glfw.Key7, Modifiers: glfw.ModAlt}, "Alt+7"},
+		{KeyChord{Key: glfw.KeyKP3, Modifiers: glfw.ModSuper}, "Super+Num3"},
+		{KeyChord{Key: glfw.KeyMinus, Modifiers: glfw.ModControl}, "Ctrl+-"},
+		{KeyChord{Key: glfw.KeyPageDown}, "PageDown"},
+		{KeyChord{Key: glfw.Key(400), Modifiers: glfw.ModShift}, "Shift+Key400"},
+	}
+	for _, test := range tests {
+		if got := test.chord.String(); got != test.want {
+			t.Errorf("%#v.String() = %q, want %q", test.chord, got, test.want)
+		}
+	}
+}
+
+func TestParseKeyChord(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    KeyChord
+		wantErr bool
+	}{
+		{"", KeyChord{}, false},
+		{"Ctrl+S", KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModControl}, false},
+		{"ctrl+shift+s", KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModControl | glfw.ModShift}, false},
+		{" Alt + Enter ", KeyChord{Key: glfw.KeyEnter, Modifiers: glfw.ModAlt}, false},
+		{"Cmd+Q", KeyChord{Key: glfw.KeyQ, Modifiers: glfw.ModSuper}, false},
+		{"Control+F12", KeyChord{Key: glfw.KeyF12, Modifiers: glfw.ModControl}, false},
+		{"Key400", KeyChord{Key: glfw.Key(400)}, false},
+		{"Hyper+A", KeyChord{}, true},
+		{"Ctrl+Foo", KeyChord{}, true},
+		{"Ctrl+", KeyChord{}, true},
+	}
+	for _, test := range tests {
+		got, err := ParseKeyChord(test.text)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseKeyChord(%q) error = %v, want error %v", test.text, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseKeyChord(%q) = %#v, want %#v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestKeyChordRoundTrip(t *testing.T) {
+	for key := range keyNames {
+		for _, modifiers := range []glfw.ModifierKey{0, glfw.ModControl, glfw.ModAlt | glfw.ModShift, chordModifiers} {
+			chord := KeyChord{Key: key, Modifiers: modifiers}
+			got, err := ParseKeyChord(chord.String())
+			if err != nil || got != chord {
+				t.Errorf("ParseKeyChord(%q) = %#v, %v, want %#v", chord.String(), got, err, chord)
+			}
+		}
+	}
+}
+
+func TestSaveLoadBindings(t *testing.T) {
+	save := KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModControl}
+	saveAs := KeyChord{Key: glfw.KeyS, Modifiers: glfw.ModControl | glfw.ModShift}
+	open := KeyChord{Key: glfw.KeyO, Modifiers: glfw.ModControl}
+
+	r := newTestRegistry()
+	saveAction := r.Register("file.save", "Save", save, func() {})
+	r.Register("file.open", "Open", open, func() {})
+	closeAction := r.Register("file.close", "Close", KeyChord{Key: glfw.KeyW, Modifiers: glfw.ModControl}, func() {})
+	r.SetChord(saveAction, saveAs)
+	r.SetChord(closeAction, KeyChord{})
+
+	var buffer bytes.Buffer
+	if err := r.SaveBindings(&buffer); err != nil {
+		t.Fatalf("SaveBindings() error = %v", err)
+	}
+	var saved map[string]string
+	if err := json.Unmarshal(buffer.Bytes(), &saved); err != nil {
+		t.Fatalf("SaveBindings() wrote invalid JSON %q: %v", buffer.String(), err)
+	}
+	want := map[string]string{"file.save": "Ctrl+Shift+S", "file.close": ""}
+	if len(saved) != len(want) || saved["file.save"] != want["file.save"] || saved["file.close"] != want["file.close"] {
+		t.Errorf("SaveBindings() = %v, want %v (the default shortcuts are not saved)", saved, want)
+	}
+
+	// the bindings loaded before the registration apply to it
+	loaded := newTestRegistry()
+	if err := loaded.LoadBindings(bytes.NewReader(buffer.Bytes())); err != nil {
+		t.Fatalf("LoadBindings() error = %v", err)
+	}
+	tests := []struct {
+		id, label string
+		chord     KeyChord
+		want      KeyChord
+	}{
+		{"file.save", "Save", save, saveAs},
+		{"file.open", "Open", open, open},
+		{"file.close", "Close", KeyChord{Key: glfw.KeyW, Modifiers: glfw.ModControl}, KeyChord{}},
+	}
+	for _, test := range tests {
+		action := loaded.Register(test.id, test.label, test.chord, func() {})
+		if action.Chord() != test.want {
+			t.Errorf("%s: Chord() = %v, want %v", test.id, action.Chord(), test.want)
+		}
+		if action.DefaultChord() != test.chord {
+			t.Errorf("%s: DefaultChord() = %v, want %v", test.id, action.DefaultChord(), test.chord)
+		}
+	}
+
+	loaded.ResetChords()
+	if got := loaded.Action("file.save").Chord(); got != save {
+		t.Errorf("after ResetChords(), Chord() = %v, want %v", got, save)
+	}
+
+	if err := loaded.LoadBindings(bytes.NewReader([]byte(`{"file.save": "Ctrl+Nope"}`))); err == nil {
+		t.Errorf("LoadBindings() accepted an unknown key")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	screen1, screen2 := &Screen{}, &Screen{}
+	window := &Window{}
+	widget1, widget2 := &WidgetImplement{}, &WidgetImplement{}
+	chord := KeyChord{Key: glfw.KeyN, Modifiers: glfw.ModControl}
+	other := KeyChord{Key: glfw.KeyM, Modifiers: glfw.ModControl}
+
+	type binding struct {
+		chord   KeyChord
+		scope   ActionScope
+		context Widget
+	}
+	tests := []struct {
+		name string
+		a, b binding
+		want bool
+	}{
+		{"any screen twice", binding{chord, ActionScopeScreen, nil}, binding{chord, ActionScopeScreen, nil}, true},
+		{"any screen and one screen", binding{chord, ActionScopeScreen, nil}, binding{chord, ActionScopeScreen, screen1}, true},
+		{"two screens", binding{chord, ActionScopeScreen, screen1}, binding{chord, ActionScopeScreen, screen2}, false},
+		{"same widget", binding{chord, ActionScopeWidget, widget1}, binding{chord, ActionScopeWidget, widget1}, true},
+		{"two widgets", binding{chord, ActionScopeWidget, widget1}, binding{chord, ActionScopeWidget, widget2}, false},
+		{"window and screen", binding{chord, ActionScopeWindow, window}, binding{chord, ActionScopeScreen, nil}, false},
+		{"window and widget", binding{chord, ActionScopeWindow, window}, binding{chord, ActionScopeWidget, window}, false},
+		{"different chords", binding{chord, ActionScopeScreen, nil}, binding{other, ActionScopeScreen, nil}, false},
+		{"no shortcut", binding{KeyChord{}, ActionScopeScreen, nil}, binding{KeyChord{}, ActionScopeScreen, nil}, false},
+	}
+	for _, test := range tests {
+		r := newTestRegistry()
+		var reported []*Action
+		r.SetConflictCallback(func(action *Action, conflicts []*Action) {
+			reported = conflicts
+		})
+		a := r.RegisterScoped("a", "A", test.a.chord, test.a.scope, test.a.context, func() {})
+		b := r.RegisterScoped("b", "B", test.b.chord, test.b.scope, test.b.context, func() {})
+		got := len(r.Conflicts(b)) > 0
+		if got != test.want {
+			t.Errorf("%s: Conflicts() found %v, want %v", test.name, got, test.want)
+		}
+		if got != (len(r.Conflicts(a)) > 0) {
+			t.Errorf("%s: Conflicts() is not symmetric", test.name)
+		}
+		if (len(reported) > 0) != test.want {
+			t.Errorf("%s: the conflict callback reported %v, want a conflict: %v", test.name, reported, test.want)
+		}
+	}
+}
+
+func TestConflictsOnScopeChange(t *testing.T) {
+	widget := &WidgetImplement{}
+	chord := KeyChord{Key: glfw.KeyDelete}
+	r := newTestRegistry()
+	var reported []*Action
+	r.SetConflictCallback(func(action *Action, conflicts []*Action) {
+		reported = conflicts
+	})
+	a := r.RegisterScoped("a", "A", chord, ActionScopeWidget, widget, func() {})
+	b := r.Register("b", "B", chord, func() {})
+	if len(reported) != 0 {
+		t.Fatalf("a widget action and a screen action were reported as conflicting")
+	}
+	b.SetScope(ActionScopeWidget, widget)
+	if len(reported) != 1 || reported[0] != a {
+		t.Errorf("SetScope() reported %v, want the conflict with %v", reported, a.ID())
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	screen := &Screen{}
+	window := &Window{}
+	focused := &WidgetImplement{}
+	unfocused := &WidgetImplement{}
+	screen.focusPath = []Widget{focused, window, screen}
+	chord := KeyChord{Key: glfw.KeyR, Modifiers: glfw.ModControl}
+
+	r := newTestRegistry()
+	var triggered string
+	register := func(id string, scope ActionScope, context Widget) *Action {
+		return r.RegisterScoped(id, id, chord, scope, context, func() {
+			triggered = id
+		})
+	}
+	screenAction := register("screen", ActionScopeScreen, nil)
+	windowAction := register("window", ActionScopeWindow, window)
+	widgetAction := register("widget", ActionScopeWidget, focused)
+	register("unfocused", ActionScopeWidget, unfocused)
+
+	tests := []struct {
+		name        string
+		setup       func()
+		modifiers   glfw.ModifierKey
+		beforeFocus bool
+		want        string
+	}{
+		{"innermost focused widget wins", func() {}, glfw.ModControl, false, "widget"},
+		{"lock keys are ignored", func() {}, glfw.ModControl | glfw.ModNumLock | glfw.ModCapsLock, false, "widget"},
+		{"other modifiers don't match", func() {}, glfw.ModControl | glfw.ModShift, false, ""},
+		{"disabled actions are skipped", func() { widgetAction.SetEnabled(false) }, glfw.ModControl, false, "window"},
+		{"screen scope last", func() { windowAction.SetEnabled(false) }, glfw.ModControl, false, "screen"},
+		{"nothing before the focus by default", func() {}, glfw.ModControl, true, ""},
+		{"scope dispatched before the focus", func() { r.SetDispatchBeforeFocus(ActionScopeScreen, true) }, glfw.ModControl, true, "screen"},
+		{"nor after it", func() {}, glfw.ModControl, false, ""},
+		{"other screen", func() { screenAction.SetScope(ActionScopeScreen, &Screen{}) }, glfw.ModControl, true, ""},
+	}
+	for _, test := range tests {
+		test.setup()
+		triggered = ""
+		handled := r.dispatch(screen, glfw.KeyR, test.modifiers, test.beforeFocus)
+		if triggered != test.want || handled != (test.want != "") {
+			t.Errorf("%s: dispatch() triggered %q (handled %v), want %q", test.name, triggered, handled, test.want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package nanogui
+
+import (
+	"github.com/maxfish/vg4go-gl4"
+	"math"
+)
+
+// EasingFunc maps the linear progress of an animation (0..1) to the eased progress
+type EasingFunc func(t float32) float32
+
+var (
+	EaseLinear EasingFunc = func(t float32) float32 {
+		return t
+	}
+	EaseInQuad EasingFunc = func(t float32) float32 {
+		return t * t
+	}
+	EaseOutQuad EasingFunc = func(t float32) float32 {
+		return t * (2 - t)
+	}
+	EaseInOutQuad EasingFunc = func(t float32) float32 {
+		if t < 0.5 {
+			return 2 * t * t
+		}
+		return -1 + (4-2*t)*t
+	}
+	EaseInCubic EasingFunc = func(t float32) float32 {
+		return t * t * t
+	}
+	EaseOutCubic EasingFunc = func(t float32) float32 {
+		t--
+		return t*t*t + 1
+	}
+	EaseInOutCubic EasingFunc = func(t float32) float32 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		t = 2*t - 2
+		return t*t*t*0.5 + 1
+	}
+	EaseOutBack EasingFunc = func(t float32) float32 {
+		const s = 1.70158
+		t--
+		return t*t*((s+1)*t+s) + 1
+	}
+	EaseOutElastic EasingFunc = func(t float32) float32 {
+		if t == 0 || t == 1 {
+			return t
+		}
+		return float32(math.Pow(2, -10*float64(t))*math.Sin((float64(t)-0.075)*(2*math.Pi)/0.3)) + 1
+	}
+)
+
+// Animation is a running tween created by Animate() and its variants
+//
+// Animations are advanced by MainLoop at the start of each frame; the loop
+// keeps drawing frames only while at least one animation is running past
+// its delay.
+type Animation struct {
+	target     Widget
+	property   string
+	start      float32
+	delay      float32
+	duration   float32
+	easing     EasingFunc
+	update     func(t float32)
+	completion func(finished bool)
+	running    bool
+}
+
+var animations []*Animation
+
+// Animate() starts an animation calling update with the eased progress (0..1) on every frame for the given duration (in seconds)
+func Animate(duration float32, update func(t float32)) *Animation {
+	// not FrameTime(): it is stale when an input event starts the animation after the loop has been idle
+	animation := &Animation{
+		start:    GetTime(),
+		duration: duration,
+		easing:   EaseOutCubic,
+		update:   update,
+		running:  true,
+	}
+	animations = append(animations, animation)
+	RequestAnimationFrame()
+	return animation
+}
+
+// AnimateFloat() tweens a float value from one value to another
+func AnimateFloat(from, to, duration float32, setter func(v float32)) *Animation {
+	return Animate(duration, func(t float32) {
+		setter(from + (to-from)*t)
+	})
+}
+
+// AnimateColor() tweens a color from one value to another
+func AnimateColor(from, to nanovgo.Color, duration float32, setter func(c nanovgo.Color)) *Animation {
+	return Animate(duration, func(t float32) {
+		setter(nanovgo.LerpRGBA(from, to, t))
+	})
+}
+
+// AnimatePosition() moves a widget to a new position; it replaces a running position animation of the same widget
+func AnimatePosition(widget Widget, x, y int, duration float32) *Animation {
+	fromX, fromY := widget.Position()
+	return animateProperty(widget, "position", duration, func(t float32) {
+		widget.SetPosition(fromX+int(float32(x-fromX)*t), fromY+int(float32(y-fromY)*t))
+	})
+}
+
+// AnimateSize() resizes a widget; it replaces a running size animation of the same widget
+func AnimateSize(widget Widget, w, h int, duration float32) *Animation {
+	fromW, fromH := widget.Size()
+	return animateProperty(widget, "size", duration, func(t float32) {
+		widget.SetSize(fromW+int(float32(w-fromW)*t), fromH+int(float32(h-fromH)*t))
+	})
+}
+
+// AnimateAlpha() fades a widget to the given opacity; it replaces a running alpha animation of the same widget
+func AnimateAlpha(widget Widget, alpha, duration float32) *Animation {
+	from := widget.Alpha()
+	return animateProperty(widget, "alpha", duration, func(t float32) {
+		widget.SetAlpha(from + (alpha-from)*t)
+	})
+}
+
+func animateProperty(widget Widget, property string, duration float32, update func(t float32)) *Animation {
+	for _, animation := range animations {
+		if animation.running && animation.target == widget && animation.property == property {
+			animation.Cancel()
+		}
+	}
+	animation := Animate(duration, update)
+	animation.target = widget
+	animation.property = property
+	return animation
+}
+
+// CancelAnimations() cancels all the property animations of a widget
+func CancelAnimations(widget Widget) {
+	for _, animation := range animations {
+		if animation.running && animation.target == widget {
+			animation.Cancel()
+		}
+	}
+}
+
+// AnimationsRunning() returns whether at least one animation is running
+func AnimationsRunning() bool {
+	for _, animation := range animations {
+		if animation.running {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEasing() sets the easing curve (EaseOutCubic by default)
+func (a *Animation) SetEasing(easing EasingFunc) *Animation {
+	a.easing = easing
+	return a
+}
+
+// SetDelay() postpones the start of the animation (in seconds)
+func (a *Animation) SetDelay(delay float32) *Animation {
+	a.delay = delay
+	return a
+}
+
+// SetCompletionCallback() sets the callback invoked when the animation ends; finished is false if it was cancelled
+func (a *Animation) SetCompletionCallback(callback func(finished bool)) *Animation {
+	a.completion = callback
+	return a
+}
+
+// Running() returns whether the animation has neither finished nor been cancelled
+func (a *Animation) Running() bool {
+	return a.running
+}
+
+// Cancel() stops the animation where it is
+func (a *Animation) Cancel() {
+	if !a.running {
+		return
+	}
+	a.running = false
+	if a.completion != nil {
+		a.completion(false)
+	}
+}
+
+// Finish() jumps to the end of the animation
+func (a *Animation) Finish() {
+	if !a.running {
+		return
+	}
+	a.running = false
+	a.update(a.easing(1.0))
+	if a.completion != nil {
+		a.completion(true)
+	}
+}
+
+// updateAnimations advances the running animations to the current frame time
+func updateAnimations() {
+	if len(animations) == 0 {
+		return
+	}
+	now := FrameTime()
+	// callbacks may start new animations: only the ones already present are advanced
+	current := animations
+	for _, animation := range current {
+		if !animation.running {
+			continue
+		}
+		elapsed := now - animation.start - animation.delay
+		if elapsed < 0 {
+			continue
+		}
+		if animation.duration <= 0 || elapsed >= animation.duration {
+			animation.Finish()
+		} else {
+			animation.update(animation.easing(elapsed / animation.duration))
+		}
+	}
+	running := animations[:0]
+	started := false
+	for _, animation := range animations {
+		if animation.running {
+			running = append(running, animation)
+			started = started || now >= animation.start+animation.delay
+		}
+	}
+	for i := len(running); i < len(animations); i++ {
+		animations[i] = nil
+	}
+	animations = running
+	// the animations still waiting for their delay wake the loop up with nextAnimationStart()
+	if started {
+		RequestAnimationFrame()
+	}
+}
+
+// nextAnimationStart returns the earliest start time of the animations waiting for their delay (0 if none)
+func nextAnimationStart() float32 {
+	var next float32
+	now := FrameTime()
+	for _, animation := range animations {
+		start := animation.start + animation.delay
+		if animation.running && start > now && (next == 0 || start < next) {
+			next = start
+		}
+	}
+	return next
+}
@@ -83,6 +83,7 @@ func (b *Button) Caption() string {
 
 func (b *Button) SetCaption(caption string) {
 	b.caption = caption
+	b.InvalidateLayout()
 }
 
 func (b *Button) BackgroundColor() nanovgo.Color {
@@ -113,6 +114,7 @@ func (b *Button) Icon() Icon {
 func (b *Button) SetIcon(i Icon) {
 	b.icon = i
 	b.imageIcon = 0
+	b.InvalidateLayout()
 }
 
 func (b *Button) ImageIcon() int {
@@ -122,6 +124,7 @@ func (b *Button) ImageIcon() int {
 func (b *Button) SetImageIcon(i int) {
 	b.imageIcon = i
 	b.icon = 0
+	b.InvalidateLayout()
 }
 func (b *Button) Flags() ButtonFlags {
 	return b.flags
@@ -137,6 +140,7 @@ func (b *Button) IconPosition() ButtonIconPosition {
 
 func (b *Button) SetIconPosition(p ButtonIconPosition) {
 	b.iconPosition = p
+	b.InvalidateLayout()
 }
 
 func (b *Button) Pushed() bool {
@@ -145,6 +149,7 @@ func (b *Button) Pushed() bool {
 
 func (b *Button) SetPushed(p bool) {
 	b.pushed = p
+	b.RequestRedraw()
 }
 
 // SetCallback set the push callback (for any type of button)
@@ -176,6 +181,7 @@ func (b *Button) FontSize() int {
 
 func (b *Button) SetFontSize(size int) {
 	b.fontSize = size
+	b.InvalidateLayout()
 }
 
 func (b *Button) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
@@ -27,6 +27,7 @@ func (c *CheckBox) Caption() string {
 
 func (c *CheckBox) SetCaption(caption string) {
 	c.caption = caption
+	c.InvalidateLayout()
 }
 
 func (c *CheckBox) Checked() bool {
@@ -35,6 +36,7 @@ func (c *CheckBox) Checked() bool {
 
 func (c *CheckBox) SetChecked(checked bool) {
 	c.checked = checked
+	c.RequestRedraw()
 }
 
 func (c *CheckBox) Pushed() bool {
@@ -43,6 +45,7 @@ func (c *CheckBox) Pushed() bool {
 
 func (c *CheckBox) SetPushed(pushed bool) {
 	c.pushed = pushed
+	c.RequestRedraw()
 }
 
 func (c *CheckBox) SetCallback(callback func(bool)) {
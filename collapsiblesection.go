@@ -0,0 +1,264 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+// Collapsible section widget
+//
+// CollapsibleSection shows a header with a chevron and a caption; clicking
+// the header (or pressing Enter or Space while it is focused) shows or hides
+// the content widget (see Content()) below it, optionally animating the
+// height. Sections in accordion mode sharing the same parent form an
+// accordion: expanding one of them collapses the others.
+type CollapsibleSection struct {
+	WidgetImplement
+
+	caption   string
+	content   Widget
+	expanded  bool
+	openness  float32
+	animated  bool
+	duration  float32
+	accordion bool
+	pushed    bool
+	animation *Animation
+	callback  func(expanded bool)
+}
+
+func NewCollapsibleSection(parent Widget, caption string, expanded ...bool) *CollapsibleSection {
+	section := &CollapsibleSection{
+		caption:  caption,
+		animated: true,
+		duration: 0.2,
+	}
+	switch len(expanded) {
+	case 0:
+	case 1:
+		section.expanded = expanded[0]
+	default:
+		panic("NewCollapsibleSection can accept only one extra parameter (expanded)")
+	}
+	InitWidget(section, parent)
+	section.content = NewWidget(section)
+	section.content.SetVisible(section.expanded)
+	if section.expanded {
+		section.openness = 1.0
+	}
+	return section
+}
+
+func (c *CollapsibleSection) Caption() string {
+	return c.caption
+}
+
+func (c *CollapsibleSection) SetCaption(caption string) {
+	c.caption = caption
+	c.InvalidateLayout()
+}
+
+// Content() returns the widget shown below the header; add the children of the section to it
+func (c *CollapsibleSection) Content() Widget {
+	return c.content
+}
+
+// Expanded() returns whether the content is shown (or being shown)
+func (c *CollapsibleSection) Expanded() bool {
+	return c.expanded
+}
+
+// SetExpanded() shows or hides the content, with an animation if enabled
+func (c *CollapsibleSection) SetExpanded(expanded bool) {
+	if c.expanded == expanded {
+		return
+	}
+	c.expanded = expanded
+	if expanded && c.accordion && c.parent != nil {
+		for _, sibling := range c.parent.Children() {
+			if section, ok := sibling.(*CollapsibleSection); ok && section != c && section.accordion {
+				section.SetExpanded(false)
+			}
+		}
+	}
+	if c.animation != nil {
+		c.animation.Cancel()
+		c.animation = nil
+	}
+	target := toF(expanded, 1.0, 0.0)
+	if expanded {
+		c.content.SetVisible(true)
+	}
+	if c.animated && c.duration > 0 {
+		c.animation = AnimateFloat(c.openness, target, c.duration, func(v float32) {
+			c.openness = v
+			c.InvalidateLayout()
+		}).SetCompletionCallback(func(finished bool) {
+			if finished {
+				c.animation = nil
+				c.content.SetVisible(c.expanded)
+			}
+		})
+	} else {
+		c.openness = target
+		c.content.SetVisible(expanded)
+		c.InvalidateLayout()
+	}
+	if c.callback != nil {
+		c.callback(expanded)
+	}
+}
+
+// Toggle() expands the section if it is collapsed and vice versa
+func (c *CollapsibleSection) Toggle() {
+	c.SetExpanded(!c.expanded)
+}
+
+// Animated() returns whether expanding and collapsing animate the height
+func (c *CollapsibleSection) Animated() bool {
+	return c.animated
+}
+
+// SetAnimated() sets whether expanding and collapsing animate the height
+func (c *CollapsibleSection) SetAnimated(animated bool) {
+	c.animated = animated
+}
+
+// Duration() returns the duration of the height animation (in seconds)
+func (c *CollapsibleSection) Duration() float32 {
+	return c.duration
+}
+
+// SetDuration() sets the duration of the height animation (in seconds)
+func (c *CollapsibleSection) SetDuration(duration float32) {
+	c.duration = duration
+}
+
+// Accordion() returns whether the section belongs to the accordion formed by its siblings
+func (c *CollapsibleSection) Accordion() bool {
+	return c.accordion
+}
+
+// SetAccordion() sets whether expanding the section collapses the sibling sections in accordion mode
+func (c *CollapsibleSection) SetAccordion(accordion bool) {
+	c.accordion = accordion
+}
+
+// SetExpandedCallback() sets the callback invoked whenever the section is expanded or collapsed
+func (c *CollapsibleSection) SetExpandedCallback(callback func(expanded bool)) {
+	c.callback = callback
+}
+
+// HeaderHeight() returns the height of the header
+func (c *CollapsibleSection) HeaderHeight() int {
+	return int(float32(c.FontSize()) * 1.8)
+}
+
+func (c *CollapsibleSection) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	padding := c.Padding()
+	_, h := LayoutSize(c.content, ctx)
+	PlaceWidget(c.content, padding.Left, c.HeaderHeight()+padding.Top, c.w-padding.Horizontal(), h+c.content.Margin().Vertical())
+	c.content.OnPerformLayout(c.content, ctx)
+}
+
+func (c *CollapsibleSection) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	fontSize := float32(c.FontSize())
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(c.theme.FontBold)
+	tw, _ := ctx.TextBounds(0, 0, c.caption)
+	w := int(tw + fontSize*2.5)
+	h := c.HeaderHeight()
+	if c.openness > 0 {
+		padding := c.Padding()
+		size := OuterSize(c.content, ctx)
+		w = maxI(w, size[0]+padding.Horizontal())
+		h += int(float32(size[1]+padding.Vertical()) * c.openness)
+	}
+	return w, h
+}
+
+func (c *CollapsibleSection) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if y-c.y >= c.HeaderHeight() && !c.pushed {
+		return c.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+	}
+	if !c.enabled || button != glfw.MouseButton1 {
+		return false
+	}
+	if down {
+		c.pushed = true
+		if !c.focused {
+			c.RequestFocus(self)
+		}
+	} else if c.pushed {
+		c.pushed = false
+		if c.Contains(x, y) && y-c.y < c.HeaderHeight() {
+			c.Toggle()
+		}
+	}
+	c.RequestRedraw()
+	return true
+}
+
+func (c *CollapsibleSection) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !c.focused || !c.enabled || action != glfw.Press {
+		return false
+	}
+	switch key {
+	case glfw.KeyEnter, glfw.KeySpace:
+		c.Toggle()
+		return true
+	}
+	return false
+}
+
+func (c *CollapsibleSection) Draw(self Widget, ctx *nanovgo.Context) {
+	x := float32(c.x)
+	y := float32(c.y)
+	w := float32(c.w)
+	hh := float32(c.HeaderHeight())
+
+	top, bottom := c.theme.ButtonGradientTopUnfocused, c.theme.ButtonGradientBotUnfocused
+	if c.pushed {
+		top, bottom = c.theme.ButtonGradientTopPushed, c.theme.ButtonGradientBotPushed
+	} else if c.mouseFocus && c.enabled {
+		top, bottom = c.theme.ButtonGradientTopFocused, c.theme.ButtonGradientBotFocused
+	}
+	ctx.BeginPath()
+	ctx.RoundedRect(x+1, y+1, w-2, hh-2, float32(c.theme.ButtonCornerRadius))
+	ctx.SetFillPaint(nanovgo.LinearGradient(x, y, x, y+hh, top, bottom))
+	ctx.Fill()
+	ctx.BeginPath()
+	ctx.RoundedRect(x+0.5, y+0.5, w-1, hh-1, float32(c.theme.ButtonCornerRadius))
+	ctx.SetStrokeColor(c.theme.BorderDark)
+	ctx.Stroke()
+
+	textColor := c.theme.TextColor
+	if !c.enabled {
+		textColor = c.theme.DisabledTextColor
+	}
+	fontSize := float32(c.FontSize())
+	icon := IconRightOpen
+	if c.expanded {
+		icon = IconDownOpen
+	}
+	ctx.SetFillColor(textColor)
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(c.theme.FontIcons)
+	ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+	ctx.TextRune(x+fontSize*0.9, y+hh*0.5, []rune{rune(icon)})
+	ctx.SetFontFace(c.theme.FontBold)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	ctx.Text(x+fontSize*1.8, y+hh*0.5, c.caption)
+
+	if c.content.Visible() && c.openness > 0 {
+		// the content is revealed progressively while the height is animated
+		ctx.Save()
+		ctx.IntersectScissor(x, y+hh, w, float32(c.h)-hh)
+		c.WidgetImplement.Draw(self, ctx)
+		ctx.Restore()
+	}
+}
+
+func (c *CollapsibleSection) String() string {
+	return c.StringHelper("CollapsibleSection", c.caption)
+}
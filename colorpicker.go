@@ -85,6 +85,7 @@ func (c *ColorPicker) SetColor(color nanovgo.Color) {
 		c.pickButton.SetBackgroundColor(color)
 		c.pickButton.SetTextColor(fgColor)
 	}
+	c.RequestRedraw()
 }
 
 func (c *ColorPicker) String() string {
@@ -54,6 +54,7 @@ func (c *ColorWheel) Color() nanovgo.Color {
 func (c *ColorWheel) SetColor(color nanovgo.Color) {
 	c.hue, c.saturation, c.lightness, _ = color.HSLA()
 	c.calculatePosition()
+	c.RequestRedraw()
 }
 
 func (c *ColorWheel) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
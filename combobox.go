@@ -106,6 +106,7 @@ func (c *ComboBox) SetItems(items []string, shortItems ...[]string) {
 		button.SetCallback(generateCallback(c, popup, i))
 	}
 	c.SetSelectedIndex(c.selectedIndex)
+	c.InvalidateLayout()
 }
 
 func (c *ComboBox) Items() []string {
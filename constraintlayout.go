@@ -0,0 +1,374 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+type Edge int
+
+const (
+	EdgeLeft Edge = iota
+	EdgeRight
+	EdgeCenterX
+	EdgeTop
+	EdgeBottom
+	EdgeCenterY
+)
+
+func (e Edge) String() string {
+	switch e {
+	case EdgeLeft:
+		return "Left"
+	case EdgeRight:
+		return "Right"
+	case EdgeCenterX:
+		return "CenterX"
+	case EdgeTop:
+		return "Top"
+	case EdgeBottom:
+		return "Bottom"
+	case EdgeCenterY:
+		return "CenterY"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+func (e Edge) axis() int {
+	if e >= EdgeTop {
+		return 1
+	}
+	return 0
+}
+
+// Constraint places an edge of a child relative to its parent or to a sibling
+//
+// The edge is placed at TargetEdge of the target, plus Percent times the
+// target's length on that axis, plus Offset. Target is the ID of a sibling
+// (see Widget.SetID()); an empty Target is the parent's content area.
+type Constraint struct {
+	Edge       Edge
+	Target     string
+	TargetEdge Edge
+	Percent    float32
+	Offset     int
+}
+
+// Pin() returns a constraint placing an edge at a distance (inwards) from the same edge of the parent
+func Pin(edge Edge, margin int) Constraint {
+	if edge == EdgeRight || edge == EdgeBottom {
+		margin = -margin
+	}
+	return Constraint{Edge: edge, TargetEdge: edge, Offset: margin}
+}
+
+// Attach() returns a constraint placing an edge relative to an edge of a sibling
+func Attach(edge Edge, target string, targetEdge Edge, offset int) Constraint {
+	return Constraint{Edge: edge, Target: target, TargetEdge: targetEdge, Offset: offset}
+}
+
+// AtPercent() returns a constraint placing an edge at a fraction (0..1) of the parent's length
+func AtPercent(edge Edge, percent float32, offset ...int) Constraint {
+	c := Constraint{Edge: edge, TargetEdge: EdgeLeft, Percent: percent}
+	if edge.axis() == 1 {
+		c.TargetEdge = EdgeTop
+	}
+	switch len(offset) {
+	case 0:
+	case 1:
+		c.Offset = offset[0]
+	default:
+		panic("AtPercent can accept only one extra parameter (offset).")
+	}
+	return c
+}
+
+func (c Constraint) String() string {
+	target := c.Target
+	if target == "" {
+		target = "parent"
+	}
+	return fmt.Sprintf("%s=%s.%s%+.0f%%%+d", c.Edge, target, c.TargetEdge, c.Percent*100, c.Offset)
+}
+
+// Constraint-based layout
+//
+// Each child declares constraints on its edges (left/right/center, top/bottom/
+// center) relative to the parent or to named siblings. With two constraints
+// on an axis the child is stretched between them, with one it keeps its
+// fixed or preferred size, with none it sits at the top-left corner.
+// Problems (unknown siblings, cycles, over-constrained axes) are reported by
+// Conflicts() after each layout; the offending constraints are ignored.
+type ConstraintLayout struct {
+	constraints map[Widget][]Constraint
+	margin      int
+	conflicts   []error
+	callback    func([]error)
+}
+
+func NewConstraintLayout(setting ...int) *ConstraintLayout {
+	var margin int
+	switch len(setting) {
+	case 0:
+	case 1:
+		margin = setting[0]
+	default:
+		panic("NewConstraintLayout can accept only one extra parameter (margin).")
+	}
+	return &ConstraintLayout{
+		constraints: make(map[Widget][]Constraint),
+		margin:      margin,
+	}
+}
+
+func (c *ConstraintLayout) Margin() int {
+	return c.margin
+}
+
+func (c *ConstraintLayout) SetMargin(m int) {
+	c.margin = m
+}
+
+// Constraints() returns the constraints of a child
+func (c *ConstraintLayout) Constraints(widget Widget) []Constraint {
+	return c.constraints[widget]
+}
+
+// AddConstraints() adds constraints to a child
+func (c *ConstraintLayout) AddConstraints(widget Widget, constraints ...Constraint) {
+	c.constraints[widget] = append(c.constraints[widget], constraints...)
+	widget.InvalidateLayout()
+}
+
+// SetConstraints() replaces the constraints of a child
+func (c *ConstraintLayout) SetConstraints(widget Widget, constraints ...Constraint) {
+	c.constraints[widget] = constraints
+	widget.InvalidateLayout()
+}
+
+// ClearConstraints() removes the constraints of a child
+func (c *ConstraintLayout) ClearConstraints(widget Widget) {
+	delete(c.constraints, widget)
+	widget.InvalidateLayout()
+}
+
+// Conflicts() returns the problems found by the last layout
+func (c *ConstraintLayout) Conflicts() []error {
+	return c.conflicts
+}
+
+// SetConflictCallback() sets the callback invoked when a layout finds problems
+func (c *ConstraintLayout) SetConflictCallback(callback func(conflicts []error)) {
+	c.callback = callback
+}
+
+// constraintSolver places the children of one widget
+type constraintSolver struct {
+	layout   *ConstraintLayout
+	ctx      *nanovgo.Context
+	origin   [2]int
+	area     [2]int
+	siblings map[string]Widget
+	state    map[Widget]int
+	rects    map[Widget][4]int
+}
+
+const (
+	solverVisiting = 1
+	solverDone     = 2
+)
+
+// rectEdge returns the position of an edge of a solved rectangle (x, y, w, h)
+func rectEdge(rect [4]int, edge Edge) int {
+	switch edge {
+	case EdgeLeft:
+		return rect[0]
+	case EdgeRight:
+		return rect[0] + rect[2]
+	case EdgeCenterX:
+		return rect[0] + rect[2]/2
+	case EdgeTop:
+		return rect[1]
+	case EdgeBottom:
+		return rect[1] + rect[3]
+	case EdgeCenterY:
+		return rect[1] + rect[3]/2
+	}
+	return 0
+}
+
+func (s *constraintSolver) conflict(format string, args ...interface{}) {
+	s.layout.conflicts = append(s.layout.conflicts, fmt.Errorf(format, args...))
+}
+
+// solve computes the rectangle of a child, solving the siblings it depends on first
+func (s *constraintSolver) solve(child Widget) [4]int {
+	switch s.state[child] {
+	case solverDone:
+		return s.rects[child]
+	case solverVisiting:
+		s.conflict("ConstraintLayout: cyclic constraints involving %s", child)
+		return s.rects[child]
+	}
+	s.state[child] = solverVisiting
+
+	hW, hH := LayoutSize(child, s.ctx)
+	fW, fH := child.FixedSize()
+	size := [2]int{hW, hH}
+	margin := child.Margin()
+	fixed := [2]bool{fW > 0, fH > 0}
+	// until it is solved, a child in a cycle is seen at the origin with its size
+	s.rects[child] = [4]int{s.origin[0], s.origin[1], size[0], size[1]}
+
+	var edges [6]*int
+	for _, constraint := range s.layout.constraints[child] {
+		if constraint.Edge.axis() != constraint.TargetEdge.axis() {
+			s.conflict("ConstraintLayout: %s of %s mixes horizontal and vertical edges", constraint, child)
+			continue
+		}
+		if edges[constraint.Edge] != nil {
+			s.conflict("ConstraintLayout: %s of %s is constrained twice", constraint.Edge, child)
+			continue
+		}
+		target := [4]int{s.origin[0], s.origin[1], s.area[0], s.area[1]}
+		if constraint.Target != "" {
+			sibling, ok := s.siblings[constraint.Target]
+			if !ok {
+				s.conflict("ConstraintLayout: %s of %s refers to an unknown sibling", constraint, child)
+				continue
+			}
+			target = s.solve(sibling)
+		}
+		value := rectEdge(target, constraint.TargetEdge) + int(constraint.Percent*float32(target[2+constraint.Edge.axis()])) + constraint.Offset
+		// the margin of the child pushes its edges inwards
+		switch constraint.Edge {
+		case EdgeLeft:
+			value += margin.Left
+		case EdgeRight:
+			value -= margin.Right
+		case EdgeTop:
+			value += margin.Top
+		case EdgeBottom:
+			value -= margin.Bottom
+		}
+		edges[constraint.Edge] = &value
+	}
+
+	var rect [4]int
+	for axis := 0; axis < 2; axis++ {
+		start, end, center := edges[axis*3], edges[axis*3+1], edges[axis*3+2]
+		if start != nil && end != nil && center != nil {
+			s.conflict("ConstraintLayout: %s is over-constrained on the %s axis", child, Orientation(axis))
+			center = nil
+		}
+		if fixed[axis] && ((start != nil && end != nil) || (center != nil && (start != nil || end != nil))) {
+			s.conflict("ConstraintLayout: %s has a fixed size and two constraints on the %s axis", child, Orientation(axis))
+			// keep the first one (start, end, center)
+			if start != nil {
+				end, center = nil, nil
+			} else {
+				center = nil
+			}
+		}
+		pos, length := s.origin[axis]+toI(axis == 0, margin.Left, margin.Top), size[axis]
+		switch {
+		case start != nil && end != nil:
+			pos, length = *start, *end-*start
+		case start != nil && center != nil:
+			pos, length = *start, 2*(*center-*start)
+		case end != nil && center != nil:
+			length = 2 * (*end - *center)
+			pos = *end - length
+		case start != nil:
+			pos = *start
+		case end != nil:
+			pos = *end - length
+		case center != nil:
+			pos = *center - length/2
+		}
+		if length < 0 {
+			s.conflict("ConstraintLayout: the constraints of %s give a negative size on the %s axis", child, Orientation(axis))
+			length = 0
+		}
+		if length != size[axis] {
+			// a stretched child keeps its size policy and bounds
+			length = fitLength(child, axis, length, size[axis])
+		}
+		rect[axis] = pos
+		rect[2+axis] = length
+	}
+	s.rects[child] = rect
+	s.state[child] = solverDone
+	return rect
+}
+
+func (c *ConstraintLayout) newSolver(widget Widget, ctx *nanovgo.Context, size [2]int) *constraintSolver {
+	solver := &constraintSolver{
+		layout:   c,
+		ctx:      ctx,
+		siblings: make(map[string]Widget),
+		state:    make(map[Widget]int),
+		rects:    make(map[Widget][4]int),
+	}
+	insets := ContentInsets(widget)
+	solver.origin = [2]int{c.margin + insets.Left, c.margin + insets.Top}
+	solver.area = [2]int{size[0] - solver.origin[0] - c.margin - insets.Right, size[1] - solver.origin[1] - c.margin - insets.Bottom}
+	for _, child := range widget.Children() {
+		if child.ID() != "" {
+			solver.siblings[child.ID()] = child
+		}
+	}
+	return solver
+}
+
+func (c *ConstraintLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+	fW, fH := widget.FixedSize()
+	size := [2]int{toI(fW > 0, fW, widget.Width()), toI(fH > 0, fH, widget.Height())}
+	c.conflicts = nil
+	// forget the constraints of the removed children
+	for child := range c.constraints {
+		if child.Parent() != widget {
+			delete(c.constraints, child)
+		}
+	}
+	solver := c.newSolver(widget, ctx, size)
+	for _, child := range widget.Children() {
+		if !child.Visible() || child.IsPositionAbsolute() {
+			child.OnPerformLayout(child, ctx)
+			continue
+		}
+		rect := solver.solve(child)
+		child.SetPosition(rect[0], rect[1])
+		child.SetSize(rect[2], rect[3])
+		child.OnPerformLayout(child, ctx)
+	}
+	if len(c.conflicts) > 0 && c.callback != nil {
+		c.callback(c.conflicts)
+	}
+}
+
+// PreferredSize() returns a size fitting every child at its preferred size with the offsets of its constraints
+func (c *ConstraintLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+	insets := ContentInsets(widget)
+	origin := [2]int{c.margin + insets.Left, c.margin + insets.Top}
+	var size [2]int
+	for _, child := range widget.Children() {
+		if !child.Visible() || child.IsPositionAbsolute() {
+			continue
+		}
+		needed := OuterSize(child, ctx)
+		for _, constraint := range c.constraints[child] {
+			if constraint.Target == "" && constraint.Percent == 0 {
+				needed[constraint.Edge.axis()] += absI(constraint.Offset)
+			}
+		}
+		size[0] = maxI(size[0], needed[0])
+		size[1] = maxI(size[1], needed[1])
+	}
+	return size[0] + origin[0] + c.margin + insets.Right, size[1] + origin[1] + c.margin + insets.Bottom
+}
+
+func (c *ConstraintLayout) String() string {
+	return fmt.Sprintf("ConstraintLayout[%d children]", len(c.constraints))
+}
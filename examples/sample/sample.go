@@ -39,7 +39,7 @@ func (a *Application) init() {
 	demo.MiscWidgetsDemo(a.screen)
 	demo.GridDemo(a.screen)
 
-	a.screen.SetDrawContentsCallback(func() {
+	nanogui.Every(0.05, func() {
 		a.progress.SetValue(float32(math.Mod(float64(nanogui.GetTime())/10, 1.0)))
 	})
 
@@ -61,6 +61,7 @@ func main() {
 	app.screen.DrawAll()
 	app.screen.SetVisible(true)
 	nanogui.MainLoop()
+	app.screen.Dispose()
 }
 
 func loadImageDirectory(ctx *nanovgo.Context, dir string) []nanogui.Image {
@@ -23,10 +23,24 @@ func (a *Application) init() {
 	window.SetFixedSize(400, 300)
 	window.SetLayout(nanogui.NewBoxLayout(nanogui.Vertical, nanogui.Middle, 10, 20))
 
-	tab := nanogui.NewTabHeader(window)
-
-	tab.AddTab(0,"First")
-	tab.AddTab(0,"Second")
+	tabWidget := nanogui.NewTabWidget(window)
+	tabWidget.SetFixedSize(360, 200)
+
+	first := tabWidget.CreateTab("First")
+	first.SetLayout(nanogui.NewGroupLayout())
+	nanogui.NewLabel(first, "Drag the tabs to reorder them")
+	tabWidget.SetTabIcon(0, nanogui.IconHome)
+
+	second := tabWidget.CreateTab("Second")
+	second.SetLayout(nanogui.NewGroupLayout())
+	nanogui.NewLabel(second, "Ctrl+Tab cycles through the tabs")
+	tabWidget.SetTabClosable(1, true)
+
+	third := tabWidget.CreateTab("Closable")
+	third.SetLayout(nanogui.NewGroupLayout())
+	nanogui.NewButton(third, "Button")
+	tabWidget.SetTabClosable(2, true)
+	tabWidget.SetActiveTab(0)
 
 	//nanogui.SetDebug(true)
 	a.screen.PerformLayout()
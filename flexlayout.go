@@ -0,0 +1,383 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+type FlexJustify int
+
+const (
+	JustifyStart FlexJustify = iota
+	JustifyCenter
+	JustifyEnd
+	JustifySpaceBetween
+	JustifySpaceAround
+)
+
+func (j FlexJustify) String() string {
+	switch j {
+	case JustifyStart:
+		return "Start"
+	case JustifyCenter:
+		return "Center"
+	case JustifyEnd:
+		return "End"
+	case JustifySpaceBetween:
+		return "SpaceBetween"
+	case JustifySpaceAround:
+		return "SpaceAround"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// FlexItem holds how a child of a FlexLayout is sized along the main axis
+//
+// Basis is the initial size (0: the fixed or preferred size). The space left
+// in a line is distributed among the children proportionally to Grow; when the
+// children don't fit, they are shrunk proportionally to Shrink times their basis.
+// Children with a fixed size on the main axis neither grow nor shrink.
+type FlexItem struct {
+	Grow   float32
+	Shrink float32
+	Basis  int
+}
+
+func NewFlexItem(grow, shrink float32, basis ...int) FlexItem {
+	item := FlexItem{
+		Grow:   grow,
+		Shrink: shrink,
+	}
+	switch len(basis) {
+	case 0:
+	case 1:
+		item.Basis = basis[0]
+	default:
+		panic("NewFlexItem can accept only one extra parameter (basis).")
+	}
+	return item
+}
+
+// Flexbox-style layout
+//
+// FlexLayout places the children in a row (or a column), growing and
+// shrinking them according to their FlexItem, distributing the free space
+// as set by the justification and aligning them on the cross axis. With
+// wrapping enabled the children flow into several lines when they don't fit.
+type FlexLayout struct {
+	orientation Orientation
+	justify     FlexJustify
+	alignment   Alignment
+	wrap        bool
+	margin      int
+	gap         int
+	items       map[Widget]FlexItem
+}
+
+func NewFlexLayout(orientation Orientation, setting ...int) *FlexLayout {
+	var margin, gap int
+	switch len(setting) {
+	case 0:
+	case 1:
+		margin = setting[0]
+	case 2:
+		margin = setting[0]
+		gap = setting[1]
+	default:
+		panic("NewFlexLayout can accept extra parameter upto 2 (margin, gap).")
+	}
+	return &FlexLayout{
+		orientation: orientation,
+		alignment:   Fill,
+		margin:      margin,
+		gap:         gap,
+		items:       make(map[Widget]FlexItem),
+	}
+}
+
+func (f *FlexLayout) Orientation() Orientation {
+	return f.orientation
+}
+
+func (f *FlexLayout) SetOrientation(o Orientation) {
+	f.orientation = o
+}
+
+// Justify() returns how the free space of a line is distributed
+func (f *FlexLayout) Justify() FlexJustify {
+	return f.justify
+}
+
+// SetJustify() sets how the free space of a line is distributed (used when no child grows)
+func (f *FlexLayout) SetJustify(j FlexJustify) {
+	f.justify = j
+}
+
+// Alignment() returns how the children are aligned on the cross axis of their line
+func (f *FlexLayout) Alignment() Alignment {
+	return f.alignment
+}
+
+// SetAlignment() sets how the children are aligned on the cross axis of their line
+func (f *FlexLayout) SetAlignment(a Alignment) {
+	f.alignment = a
+}
+
+func (f *FlexLayout) Wrap() bool {
+	return f.wrap
+}
+
+// SetWrap() sets whether the children flow into several lines when they don't fit
+func (f *FlexLayout) SetWrap(wrap bool) {
+	f.wrap = wrap
+}
+
+func (f *FlexLayout) Margin() int {
+	return f.margin
+}
+
+func (f *FlexLayout) SetMargin(m int) {
+	f.margin = m
+}
+
+// Gap() returns the space between adjacent children and between lines
+func (f *FlexLayout) Gap() int {
+	return f.gap
+}
+
+// SetGap() sets the space between adjacent children and between lines
+func (f *FlexLayout) SetGap(g int) {
+	f.gap = g
+}
+
+// Item() returns the flex properties of a child (no grow, shrink 1, preferred size by default)
+func (f *FlexLayout) Item(widget Widget) FlexItem {
+	if item, ok := f.items[widget]; ok {
+		return item
+	}
+	return FlexItem{Shrink: 1}
+}
+
+// SetItem() sets the flex properties of a child
+func (f *FlexLayout) SetItem(widget Widget, item FlexItem) {
+	f.items[widget] = item
+	widget.InvalidateLayout()
+}
+
+// flexEntry is a child being laid out
+type flexEntry struct {
+	widget Widget
+	item   FlexItem
+	fixed  [2]int
+	hint   [2]int
+	size   [2]int
+}
+
+// entries returns the laid out children with their initial size
+func (f *FlexLayout) entries(widget Widget, ctx *nanovgo.Context) []*flexEntry {
+	axis1 := int(f.orientation)
+	var entries []*flexEntry
+	for _, child := range widget.Children() {
+		if !child.Visible() || child.IsPositionAbsolute() {
+			continue
+		}
+		entry := &flexEntry{
+			widget: child,
+			item:   f.Item(child),
+		}
+		entry.fixed[0], entry.fixed[1] = child.FixedSize()
+		entry.hint = OuterSize(child, ctx)
+		entry.size = entry.hint
+		if entry.item.Basis > 0 && entry.fixed[axis1] == 0 {
+			margin := child.Margin()
+			entry.size[axis1] = entry.item.Basis + toI(axis1 == 0, margin.Horizontal(), margin.Vertical())
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// lines splits the children into lines fitting the available length (everything fits if length <= 0)
+func (f *FlexLayout) lines(entries []*flexEntry, length int) [][]*flexEntry {
+	axis1 := int(f.orientation)
+	var lines [][]*flexEntry
+	var line []*flexEntry
+	lineLength := 0
+	for _, entry := range entries {
+		if f.wrap && length > 0 && len(line) > 0 && lineLength+f.gap+entry.size[axis1] > length {
+			lines = append(lines, line)
+			line = nil
+		}
+		if len(line) == 0 {
+			lineLength = entry.size[axis1]
+		} else {
+			lineLength += f.gap + entry.size[axis1]
+		}
+		line = append(line, entry)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// contentArea returns the offset and the size available to the children
+func (f *FlexLayout) contentArea(widget Widget, size [2]int) (offset [2]int, area [2]int) {
+	insets := ContentInsets(widget)
+	offset = [2]int{f.margin + insets.Left, f.margin + insets.Top}
+	area = [2]int{size[0] - offset[0] - f.margin - insets.Right, size[1] - offset[1] - f.margin - insets.Bottom}
+	return
+}
+
+func (f *FlexLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+	fW, fH := widget.FixedSize()
+	containerSize := [2]int{
+		toI(fW > 0, fW, widget.Width()),
+		toI(fH > 0, fH, widget.Height()),
+	}
+	axis1 := int(f.orientation)
+	axis2 := (axis1 + 1) % 2
+	offset, area := f.contentArea(widget, containerSize)
+
+	// forget the items of the removed children
+	for child := range f.items {
+		if child.Parent() != widget {
+			delete(f.items, child)
+		}
+	}
+
+	entries := f.entries(widget, ctx)
+	lines := f.lines(entries, area[axis1])
+	cross := offset[axis2]
+	for _, line := range lines {
+		used := f.gap * (len(line) - 1)
+		lineCross := 0
+		for _, entry := range line {
+			used += entry.size[axis1]
+			lineCross = maxI(lineCross, entry.size[axis2])
+		}
+		if !f.wrap || len(lines) == 1 {
+			lineCross = maxI(lineCross, area[axis2])
+		}
+
+		// grow or shrink the children
+		free := area[axis1] - used
+		var totalGrow, totalShrink float32
+		for _, entry := range line {
+			if entry.fixed[axis1] > 0 {
+				continue
+			}
+			totalGrow += entry.item.Grow
+			totalShrink += entry.item.Shrink * float32(entry.size[axis1])
+		}
+		if free > 0 && totalGrow > 0 {
+			remaining := free
+			for _, entry := range line {
+				if entry.fixed[axis1] > 0 || entry.item.Grow == 0 {
+					continue
+				}
+				extra := int(float32(free) * entry.item.Grow / totalGrow)
+				entry.size[axis1] += extra
+				remaining -= extra
+			}
+			// rounding leftovers go to the last growing child
+			for i := len(line) - 1; i >= 0; i-- {
+				if line[i].fixed[axis1] == 0 && line[i].item.Grow > 0 {
+					line[i].size[axis1] += remaining
+					break
+				}
+			}
+			free = 0
+		} else if free < 0 && totalShrink > 0 {
+			for _, entry := range line {
+				if entry.fixed[axis1] > 0 {
+					continue
+				}
+				shrink := int(float32(-free) * entry.item.Shrink * float32(entry.size[axis1]) / totalShrink)
+				entry.size[axis1] = maxI(0, entry.size[axis1]-shrink)
+			}
+			free = 0
+		}
+
+		// distribute the free space
+		position := offset[axis1]
+		spacing := f.gap
+		if free > 0 {
+			switch f.justify {
+			case JustifyCenter:
+				position += free / 2
+			case JustifyEnd:
+				position += free
+			case JustifySpaceBetween:
+				if len(line) > 1 {
+					spacing += free / (len(line) - 1)
+				} else {
+					position += free / 2
+				}
+			case JustifySpaceAround:
+				around := free / len(line)
+				position += around / 2
+				spacing += around
+			}
+		}
+
+		for _, entry := range line {
+			// keep the size policy and the bounds of the child
+			entry.size[axis1] = fitOuter(entry.widget, axis1, entry.size[axis1], entry.hint[axis1])
+			var pos [2]int
+			pos[axis1] = position
+			pos[axis2] = cross
+			switch ChildAlignment(entry.widget, axis2, f.alignment) {
+			case Middle:
+				pos[axis2] += (lineCross - entry.size[axis2]) / 2
+			case Maximum:
+				pos[axis2] += lineCross - entry.size[axis2]
+			case Fill:
+				entry.size[axis2] = fitOuter(entry.widget, axis2, lineCross, entry.hint[axis2])
+			}
+			PlaceWidget(entry.widget, pos[0], pos[1], entry.size[0], entry.size[1])
+			entry.widget.OnPerformLayout(entry.widget, ctx)
+			position += entry.size[axis1] + spacing
+		}
+		cross += lineCross + f.gap
+	}
+	for _, child := range widget.Children() {
+		if child.Visible() && child.IsPositionAbsolute() {
+			child.OnPerformLayout(child, ctx)
+		}
+	}
+}
+
+func (f *FlexLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+	axis1 := int(f.orientation)
+	axis2 := (axis1 + 1) % 2
+	fW, fH := widget.FixedSize()
+	offset, area := f.contentArea(widget, [2]int{fW, fH})
+	length := 0
+	if f.wrap && [2]int{fW, fH}[axis1] > 0 {
+		// a fixed length decides how the children wrap
+		length = area[axis1]
+	}
+	var size [2]int
+	lines := f.lines(f.entries(widget, ctx), length)
+	for i, line := range lines {
+		lineLength := f.gap * (len(line) - 1)
+		lineCross := 0
+		for _, entry := range line {
+			lineLength += entry.size[axis1]
+			lineCross = maxI(lineCross, entry.size[axis2])
+		}
+		size[axis1] = maxI(size[axis1], lineLength)
+		if i > 0 {
+			size[axis2] += f.gap
+		}
+		size[axis2] += lineCross
+	}
+	insets := ContentInsets(widget)
+	return size[0] + offset[0] + f.margin + insets.Right, size[1] + offset[1] + f.margin + insets.Bottom
+}
+
+func (f *FlexLayout) String() string {
+	return fmt.Sprintf("FlexLayout[%s,%s,%s]", f.orientation, f.justify, f.alignment)
+}
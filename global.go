@@ -4,13 +4,18 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"runtime"
-	"sync"
 	"time"
 )
 
 var mainloopActive bool = false
 var startTime time.Time
 var debugFlag bool
+var frameTime float32
+var animationRequested bool
+var animationFrame bool
+var quitRequested bool
+var beforeFrameCallback func()
+var afterFrameCallback func()
 
 func Init() {
 	runtime.LockOSThread()
@@ -33,25 +38,72 @@ func GetTime() float32 {
 	return float32(time.Now().Sub(startTime)/time.Millisecond) * 0.001
 }
 
+// FrameTime() returns the time (see GetTime()) at which the main loop started drawing the current frame
+//
+// Input events are handled while the loop waits for the next frame: use
+// GetTime() to timestamp what they start.
+func FrameTime() float32 {
+	if !mainloopActive {
+		return GetTime()
+	}
+	return frameTime
+}
+
+// RequestAnimationFrame() asks the main loop to draw the next frame right away instead of waiting for events
+func RequestAnimationFrame() {
+	animationRequested = true
+}
+
+// Quit() makes MainLoop() return at the start of the next frame; it can be called from any goroutine
+func Quit() {
+	Post(func() {
+		quitRequested = true
+	})
+}
+
+// SetBeforeFrameCallback() sets the function MainLoop() calls on every frame before drawing the screens
+func SetBeforeFrameCallback(callback func()) {
+	beforeFrameCallback = callback
+}
+
+// SetAfterFrameCallback() sets the function MainLoop() calls on every frame after drawing the screens
+func SetAfterFrameCallback(callback func()) {
+	afterFrameCallback = callback
+}
+
+// MainLoop() runs the event loop until all the screens are closed or Quit() is called
+//
+// The loop sleeps until an event arrives or a timer is due; a screen is drawn only when it
+// needs it (see Widget.RequestRedraw() and Screen.RequestRedrawAt()), and all
+// the screens are drawn continuously while an animation is running.
 func MainLoop() {
 	mainloopActive = true
+	acceptTasks(true)
 
-	var wg sync.WaitGroup
-
-	/* If there are no mouse/keyboard events, try to refresh the
-	view roughly every 50 ms; this is to support animations
-	such as progress bars while keeping the system load
-	reasonably low */
-	wg.Add(1)
-	go func() {
-		for mainloopActive {
-			time.Sleep(50 * time.Millisecond)
-			glfw.PostEmptyEvent()
-		}
-		wg.Done()
-	}()
 	for mainloopActive {
+		frameTime = GetTime()
+		animationFrame = animationRequested
+		animationRequested = false
+		// posted tasks and timers may change anything: redraw everything after them
+		if runTasks() {
+			animationFrame = true
+		}
+		if quitRequested {
+			break
+		}
+		if runTimers() {
+			animationFrame = true
+		}
+		updateAnimations()
+		if beforeFrameCallback != nil {
+			beforeFrameCallback()
+		}
+		animationFrame = animationFrame || animationRequested
 		haveActiveScreen := false
+		nextRedraw := nextTimerDeadline()
+		if t := nextAnimationStart(); t != 0 && (nextRedraw == 0 || t < nextRedraw) {
+			nextRedraw = t
+		}
 		for _, screen := range nanoguiScreens {
 			if !screen.Visible() {
 				continue
@@ -62,15 +114,29 @@ func MainLoop() {
 			//screen.DebugPrint()
 			screen.DrawAll()
 			haveActiveScreen = true
+			if t := screen.RedrawTime(); t != 0 && (nextRedraw == 0 || t < nextRedraw) {
+				nextRedraw = t
+			}
+		}
+		if afterFrameCallback != nil {
+			afterFrameCallback()
 		}
 		if !haveActiveScreen {
-			mainloopActive = false
 			break
 		}
-		glfw.WaitEvents()
+		if animationRequested {
+			glfw.WaitEventsTimeout(1.0 / 60.0)
+		} else if nextRedraw != 0 {
+			glfw.WaitEventsTimeout(float64(maxF(nextRedraw-GetTime(), 0.001)))
+		} else {
+			glfw.WaitEvents()
+		}
 	}
-
-	wg.Wait()
+	acceptTasks(false)
+	// the goroutines blocked in Invoke() get their tasks run before the loop returns
+	runTasks()
+	mainloopActive = false
+	quitRequested = false
 }
 
 func SetDebug(d bool) {
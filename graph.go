@@ -38,6 +38,7 @@ func (g *Graph) Caption() string {
 
 func (g *Graph) SetCaption(caption string) {
 	g.caption = caption
+	g.RequestRedraw()
 }
 
 func (g *Graph) Header() string {
@@ -46,6 +47,7 @@ func (g *Graph) Header() string {
 
 func (g *Graph) SetHeader(header string) {
 	g.header = header
+	g.RequestRedraw()
 }
 
 func (g *Graph) Footer() string {
@@ -54,6 +56,7 @@ func (g *Graph) Footer() string {
 
 func (g *Graph) SetFooter(footer string) {
 	g.footer = footer
+	g.RequestRedraw()
 }
 
 func (g *Graph) BackgroundColor() nanovgo.Color {
@@ -86,6 +89,7 @@ func (g *Graph) Values() []float32 {
 
 func (g *Graph) SetValues(values []float32) {
 	g.values = values
+	g.RequestRedraw()
 }
 
 func (g *Graph) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
@@ -51,6 +51,7 @@ func (i *ImageView) Image() Image {
 func (i *ImageView) SetImage(img Image) {
 	i.image = img
 	i.fit()
+	i.InvalidateLayout()
 }
 
 func (i *ImageView) StretchMode() ImageStretchMode {
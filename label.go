@@ -35,6 +35,7 @@ func (l *Label) Caption() string {
 // SetCaption() sets the label's text caption
 func (l *Label) SetCaption(caption string) {
 	l.caption = caption
+	l.InvalidateLayout()
 }
 
 // Font() gets the currently active font
@@ -48,6 +49,7 @@ func (l *Label) Font() string {
 // SetFont() sets the currently active font (2 are available by default: 'sans' and 'sans-bold')
 func (l *Label) SetFont(fontFace string) {
 	l.fontFace = fontFace
+	l.InvalidateLayout()
 }
 
 // Color() gets the label color
@@ -58,6 +60,7 @@ func (l *Label) Color() nanovgo.Color {
 // SetColor() sets the label color
 func (l *Label) SetColor(color nanovgo.Color) {
 	l.color = color
+	l.RequestRedraw()
 }
 
 func (l *Label) ColumnWidth() int {
@@ -66,6 +69,7 @@ func (l *Label) ColumnWidth() int {
 
 func (l *Label) SetColumnWidth(width int) {
 	l.columnWidth = width
+	l.InvalidateLayout()
 }
 
 func (l *Label) Wrap() bool {
@@ -74,6 +78,7 @@ func (l *Label) Wrap() bool {
 
 func (l *Label) SetWrap(wrap bool) {
 	l.wrap = wrap
+	l.InvalidateLayout()
 }
 
 func (l *Label) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
@@ -48,6 +48,189 @@ func (o Orientation) String() string {
 
 }
 
+// Insets holds the space around (margin) or inside (padding) the edges of a widget
+type Insets struct {
+	Left, Top, Right, Bottom int
+}
+
+// NewInsets() returns insets from (all), (horizontal, vertical) or (left, top, right, bottom)
+func NewInsets(values ...int) Insets {
+	switch len(values) {
+	case 1:
+		return Insets{values[0], values[0], values[0], values[0]}
+	case 2:
+		return Insets{values[0], values[1], values[0], values[1]}
+	case 4:
+		return Insets{values[0], values[1], values[2], values[3]}
+	}
+	panic("NewInsets can accept 1, 2 or 4 parameters (all), (horizontal, vertical) or (left, top, right, bottom).")
+}
+
+// Horizontal() returns the sum of the left and right insets
+func (i Insets) Horizontal() int {
+	return i.Left + i.Right
+}
+
+// Vertical() returns the sum of the top and bottom insets
+func (i Insets) Vertical() int {
+	return i.Top + i.Bottom
+}
+
+func (i Insets) String() string {
+	return fmt.Sprintf("Insets[%d,%d,%d,%d]", i.Left, i.Top, i.Right, i.Bottom)
+}
+
+// SizePolicy tells layout generators how a widget may be resized on one axis
+//
+// SizePreferred: the widget gets its preferred size, or the space given by
+// the layout (e.g. with the Fill alignment).
+// SizeFixed: the widget always gets its preferred size.
+// SizeMinimum: the preferred size is the minimum; the widget may grow.
+// SizeExpanding: the widget takes as much space as is available: it fills
+// its cell regardless of the alignment, and in box and group layouts the
+// expanding widgets share the space left along the layout axis.
+//
+// In every case the size is bounded by the minimum and maximum sizes of the
+// widget (see SetMinSize() and SetMaxSize()), and a fixed size overrides all.
+type SizePolicy int
+
+const (
+	SizePreferred SizePolicy = iota
+	SizeFixed
+	SizeMinimum
+	SizeExpanding
+)
+
+func (p SizePolicy) String() string {
+	switch p {
+	case SizePreferred:
+		return "Preferred"
+	case SizeFixed:
+		return "Fixed"
+	case SizeMinimum:
+		return "Minimum"
+	case SizeExpanding:
+		return "Expanding"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// LayoutSize() returns the size a layout gives a widget by default: its fixed size, or its preferred size bounded by its minimum and maximum sizes
+func LayoutSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+	pW, pH := widget.PreferredSize(widget, ctx)
+	return layoutLength(widget, 0, pW), layoutLength(widget, 1, pH)
+}
+
+// FitSize() adjusts the size a layout computed for a widget (e.g. the size of its cell) to the widget's fixed size, size policies and minimum and maximum sizes
+func FitSize(widget Widget, ctx *nanovgo.Context, w, h int) (int, int) {
+	hintW, hintH := LayoutSize(widget, ctx)
+	return fitLength(widget, 0, w, hintW), fitLength(widget, 1, h, hintH)
+}
+
+// layoutLength returns the fixed length of a widget on an axis, or the preferred length bounded by its minimum and maximum
+func layoutLength(widget Widget, axis int, preferred int) int {
+	fW, fH := widget.FixedSize()
+	if fixed := toI(axis == 0, fW, fH); fixed > 0 {
+		return fixed
+	}
+	return boundLength(widget, axis, preferred)
+}
+
+// fitLength adjusts the length a layout computed on an axis to the policy and bounds of a widget (hint is its layoutLength)
+func fitLength(widget Widget, axis int, length, hint int) int {
+	fW, fH := widget.FixedSize()
+	if fixed := toI(axis == 0, fW, fH); fixed > 0 {
+		return fixed
+	}
+	switch AxisSizePolicy(widget, axis) {
+	case SizeFixed:
+		length = hint
+	case SizeMinimum:
+		length = maxI(length, hint)
+	}
+	return boundLength(widget, axis, length)
+}
+
+func boundLength(widget Widget, axis int, length int) int {
+	minW, minH := widget.MinSize()
+	maxW, maxH := widget.MaxSize()
+	if max := toI(axis == 0, maxW, maxH); max > 0 {
+		length = minI(length, max)
+	}
+	return maxI(length, toI(axis == 0, minW, minH))
+}
+
+// AxisSizePolicy() returns the size policy of a widget on an axis (0: horizontal, 1: vertical)
+func AxisSizePolicy(widget Widget, axis int) SizePolicy {
+	h, v := widget.SizePolicy()
+	if axis == 0 {
+		return h
+	}
+	return v
+}
+
+// expands returns whether a widget takes all the space available on an axis
+func expands(widget Widget, axis int) bool {
+	fW, fH := widget.FixedSize()
+	return AxisSizePolicy(widget, axis) == SizeExpanding && toI(axis == 0, fW, fH) == 0
+}
+
+// ChildAlignment() returns the alignment of a widget in its cell: Fill if it expands on that axis
+func ChildAlignment(widget Widget, axis int, align Alignment) Alignment {
+	if expands(widget, axis) {
+		return Fill
+	}
+	return align
+}
+
+// isLaidOut returns whether a layout places the widget: it is visible and not positioned absolutely
+func isLaidOut(widget Widget) bool {
+	return widget.Visible() && !widget.IsPositionAbsolute()
+}
+
+// OuterSize() returns the space a layout reserves for a widget: its layout size plus its margin
+func OuterSize(widget Widget, ctx *nanovgo.Context) [2]int {
+	w, h := LayoutSize(widget, ctx)
+	m := widget.Margin()
+	return [2]int{w + m.Horizontal(), h + m.Vertical()}
+}
+
+// fitOuter is fitLength for lengths including the margin of the widget
+func fitOuter(widget Widget, axis int, length, hint int) int {
+	m := widget.Margin()
+	margin := toI(axis == 0, m.Horizontal(), m.Vertical())
+	return fitLength(widget, axis, length-margin, hint-margin) + margin
+}
+
+// FitOuterSize() is FitSize() for a box including the margin of the widget: it returns the fitted box
+func FitOuterSize(widget Widget, ctx *nanovgo.Context, w, h int) (int, int) {
+	hint := OuterSize(widget, ctx)
+	return fitOuter(widget, 0, w, hint[0]), fitOuter(widget, 1, h, hint[1])
+}
+
+// PlaceWidget() sets the position and size of a widget from the box a layout gives it, leaving out the margin of the widget
+func PlaceWidget(widget Widget, x, y, w, h int) {
+	m := widget.Margin()
+	widget.SetPosition(x+m.Left, y+m.Top)
+	widget.SetSize(maxI(w-m.Horizontal(), 0), maxI(h-m.Vertical(), 0))
+}
+
+// ContentInsets() returns the space between the edges of a widget and the area of its children: its padding, plus the header of a window
+func ContentInsets(widget Widget) Insets {
+	insets := widget.Padding()
+	insets.Top += HeaderHeight(widget)
+	return insets
+}
+
+// HeaderHeight() returns the height of the header of a window (its title bar and menu bar), or 0 for other widgets
+func HeaderHeight(widget Widget) int {
+	if window, ok := widget.(*Window); ok {
+		return window.HeaderHeight()
+	}
+	return 0
+}
+
 type Layout interface {
 	OnPerformLayout(widget Widget, ctx *nanovgo.Context)
 	PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int)
@@ -134,20 +317,38 @@ func (b *BoxLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 	}
 	axis1 := int(b.orientation)
 	axis2 := (int(b.orientation) + 1) % 2
-	position := b.margin
+	padding := widget.Padding()
+	paddingStart := [2]int{padding.Left, padding.Top}
+	paddingSize := [2]int{padding.Horizontal(), padding.Vertical()}
+	position := b.margin + paddingStart[axis1]
 
 	var yOffset int
 
 	if _, ok := widget.(*Window); ok {
 		if b.orientation == Vertical {
-			position += widget.Theme().WindowHeaderHeight - b.margin/2
+			position += HeaderHeight(widget) - b.margin/2
 		} else {
-			yOffset = widget.Theme().WindowHeaderHeight
+			yOffset = HeaderHeight(widget)
 		}
 	}
+	crossStart := paddingStart[axis2] + yOffset
+	crossSize := containerSize[axis2] - yOffset - paddingSize[axis2]
+
+	// the expanding children share the space left along the axis
+	expanding := 0
+	for _, child := range widget.Children() {
+		if isLaidOut(child) && expands(child, axis1) {
+			expanding++
+		}
+	}
+	var extra int
+	if expanding > 0 {
+		pW, pH := b.PreferredSize(widget, ctx)
+		extra = maxI(containerSize[axis1]-[2]int{pW, pH}[axis1], 0)
+	}
 	first := true
 	for _, child := range widget.Children() {
-		if !child.Visible() {
+		if !isLaidOut(child) {
 			continue
 		}
 		if first {
@@ -155,48 +356,44 @@ func (b *BoxLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 		} else {
 			position += b.spacing
 		}
-		var fs [2]int
-		pW, pH := child.PreferredSize(child, ctx)
-		fs[0], fs[1] = child.FixedSize()
-		targetSize := [2]int{
-			toI(fs[0] > 0, fs[0], pW),
-			toI(fs[1] > 0, fs[1], pH),
+		targetSize := OuterSize(child, ctx)
+		if expanding > 0 && expands(child, axis1) {
+			share := extra / expanding
+			extra -= share
+			expanding--
+			targetSize[axis1] = fitOuter(child, axis1, targetSize[axis1]+share, targetSize[axis1])
 		}
 		var pos [2]int
-		pos[1] = yOffset
 		pos[axis1] = position
+		pos[axis2] = crossStart
 
-		switch b.alignment {
+		switch ChildAlignment(child, axis2, b.alignment) {
 		case Minimum:
 			pos[axis2] += b.margin
 		case Middle:
-			pos[axis2] += (containerSize[axis2] - yOffset - targetSize[axis2]) / 2
+			pos[axis2] += (crossSize - targetSize[axis2]) / 2
 		case Maximum:
-			pos[axis2] += containerSize[axis2] - yOffset - targetSize[axis2] - b.margin
+			pos[axis2] += crossSize - targetSize[axis2] - b.margin
 		case Fill:
 			pos[axis2] += b.margin
-			if fs[axis2] > 0 {
-				targetSize[axis2] = fs[axis2]
-			} else {
-				targetSize[axis2] = containerSize[axis2] - yOffset - b.margin*2
-			}
+			targetSize[axis2] = fitOuter(child, axis2, crossSize-b.margin*2, targetSize[axis2])
 		}
-		child.SetPosition(pos[0], pos[1])
-		child.SetSize(targetSize[0], targetSize[1])
+		PlaceWidget(child, pos[0], pos[1], targetSize[0], targetSize[1])
 		child.OnPerformLayout(child, ctx)
 		position += targetSize[axis1]
 	}
 }
 
 func (b *BoxLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
-	size := []int{2 * b.margin, 2 * b.margin}
+	padding := widget.Padding()
+	size := []int{2*b.margin + padding.Horizontal(), 2*b.margin + padding.Vertical()}
 
 	axis2Offset := 0
 	if _, ok := widget.(*Window); ok {
 		if b.orientation == Vertical {
-			size[1] += widget.Theme().WindowHeaderHeight - b.margin/2
+			size[1] += HeaderHeight(widget) - b.margin/2
 		} else {
-			axis2Offset = widget.Theme().WindowHeaderHeight
+			axis2Offset = HeaderHeight(widget)
 		}
 	}
 
@@ -205,7 +402,7 @@ func (b *BoxLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int
 	axis2 := (int(b.orientation) + 1) % 2
 
 	for _, child := range widget.Children() {
-		if !child.Visible() {
+		if !isLaidOut(child) {
 			continue
 		}
 		if first {
@@ -214,14 +411,9 @@ func (b *BoxLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int
 			size[axis1] += b.spacing
 		}
 
-		pW, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		targetSize := [2]int{
-			toI(fW > 0, fW, pW),
-			toI(fH > 0, fH, pH),
-		}
+		targetSize := OuterSize(child, ctx)
 		size[axis1] += targetSize[axis1]
-		size[axis2] = maxI(size[axis2], targetSize[axis2]+2*b.margin+axis2Offset)
+		size[axis2] = maxI(size[axis2], targetSize[axis2]+2*b.margin+axis2Offset+[2]int{padding.Horizontal(), padding.Vertical()}[axis2])
 	}
 	return size[0], size[1]
 }
@@ -323,18 +515,30 @@ func (g *GroupLayout) SetGroupSpacing(s int) {
 }
 
 func (g *GroupLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
-	height := g.margin
-	availableWidth := -g.margin * 2
+	padding := widget.Padding()
+	height := g.margin + padding.Top
+	availableWidth := -g.margin*2 - padding.Horizontal()
 	availableWidth += toI(widget.FixedWidth() > 0, widget.FixedWidth(), widget.Width())
-	window, ok := widget.(*Window)
-	if ok && window.Title() != "" {
-		height += widget.Theme().WindowHeaderHeight - g.margin/2
+	if _, ok := widget.(*Window); ok {
+		height += HeaderHeight(widget) - g.margin/2
+	}
+	// the vertically expanding children share the height left
+	expanding := 0
+	for _, child := range widget.Children() {
+		if isLaidOut(child) && expands(child, 1) {
+			expanding++
+		}
+	}
+	var extra int
+	if expanding > 0 {
+		_, pH := g.PreferredSize(widget, ctx)
+		extra = maxI(toI(widget.FixedHeight() > 0, widget.FixedHeight(), widget.Height())-pH, 0)
 	}
 	first := true
 	indent := false
 
 	for _, child := range widget.Children() {
-		if !child.Visible() {
+		if !isLaidOut(child) {
 			continue
 		}
 		label, ok := child.(*Label)
@@ -348,13 +552,16 @@ func (g *GroupLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 			indentValue = g.groupIndent
 		}
 
-		pW := availableWidth - indentValue
-		_, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		tW := toI(fW > 0, fW, pW)
-		tH := toI(fH > 0, fH, pH)
-		child.SetPosition(g.margin+indentValue, height)
-		child.SetSize(tW, tH)
+		hint := OuterSize(child, ctx)
+		tW := fitOuter(child, 0, availableWidth-indentValue, hint[0])
+		tH := hint[1]
+		if expanding > 0 && expands(child, 1) {
+			share := extra / expanding
+			extra -= share
+			expanding--
+			tH = fitOuter(child, 1, tH+share, hint[1])
+		}
+		PlaceWidget(child, padding.Left+g.margin+indentValue, height, tW, tH)
 		child.OnPerformLayout(child, ctx)
 		height += tH
 
@@ -365,18 +572,18 @@ func (g *GroupLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 }
 
 func (g *GroupLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
-	height := g.margin
-	width := g.margin * 2
+	padding := widget.Padding()
+	height := g.margin + padding.Top
+	width := g.margin*2 + padding.Horizontal()
 
-	window, ok := widget.(*Window)
-	if ok && window.Title() != "" {
-		height += widget.Theme().WindowHeaderHeight - g.margin/2
+	if _, ok := widget.(*Window); ok {
+		height += HeaderHeight(widget) - g.margin/2
 	}
 	first := true
 	indent := false
 
 	for _, child := range widget.Children() {
-		if !child.Visible() {
+		if !isLaidOut(child) {
 			continue
 		}
 		label, ok := child.(*Label)
@@ -384,22 +591,20 @@ func (g *GroupLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, i
 			height += toI(ok, g.groupSpacing, g.spacing)
 		}
 		first = false
-		pW, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		tW := toI(fW > 0, fW, pW)
-		tH := toI(fH > 0, fH, pH)
+		targetSize := OuterSize(child, ctx)
+		tW, tH := targetSize[0], targetSize[1]
 		var indentValue int
 		if indent && !ok {
 			indentValue = g.groupIndent
 		}
 		height += tH
-		width = maxI(width, tW+2*g.margin+indentValue)
+		width = maxI(width, tW+2*g.margin+padding.Horizontal()+indentValue)
 
 		if ok {
 			indent = label.Caption() != ""
 		}
 	}
-	height += g.margin
+	height += g.margin + padding.Bottom
 	return width, height
 }
 
@@ -536,9 +741,10 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 	grid := g.computeLayout(widget, ctx)
 	dim := []int{len(grid[0]), len(grid[1])}
 
-	extra := []int{0, 0}
+	padding := widget.Padding()
+	extra := []int{padding.Horizontal(), padding.Vertical()}
 	if _, ok := widget.(*Window); ok {
-		extra[1] = widget.Theme().WindowHeaderHeight - g.margin/2
+		extra[1] += HeaderHeight(widget) - g.margin/2
 	}
 
 	/* Stretch to size provided by widget */
@@ -567,7 +773,7 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 
 	axis1 := int(g.orientation)
 	axis2 := (int(g.orientation) + 1) % 2
-	start := []int{g.margin, g.margin + extra[1]}
+	start := []int{g.margin + padding.Left, g.margin + extra[1] - padding.Bottom}
 	pos := []int{start[0], start[1]}
 	numChildren := widget.ChildCount()
 	child := 0
@@ -583,22 +789,16 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 				}
 				w = children[child]
 				child++
-				if w.Visible() {
+				if isLaidOut(w) {
 					break
 				}
 			}
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			fs := []int{fw, fh}
-			targetSize := []int{
-				toI(fw > 0, fw, pw),
-				toI(fh > 0, fh, ph),
-			}
+			targetSize := OuterSize(w, ctx)
 			itemPos := []int{pos[0], pos[1]}
 			for j := 0; j < 2; j++ {
 				axis := (axis1 + j) % 2
 				item := toI(j == 0, i1, i2)
-				align := g.Alignment(axis, item)
+				align := ChildAlignment(w, axis, g.Alignment(axis, item))
 
 				switch align {
 				case Minimum:
@@ -607,11 +807,10 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 				case Maximum:
 					itemPos[axis] += grid[axis][item] - targetSize[axis]
 				case Fill:
-					targetSize[axis] = toI(fs[axis] > 0, fs[axis], grid[axis][item])
+					targetSize[axis] = fitOuter(w, axis, grid[axis][item], targetSize[axis])
 				}
 			}
-			w.SetPosition(itemPos[0], itemPos[1])
-			w.SetSize(targetSize[0], targetSize[1])
+			PlaceWidget(w, itemPos[0], itemPos[1], targetSize[0], targetSize[1])
 			w.OnPerformLayout(w, ctx)
 			pos[axis1] += grid[axis1][i1] + g.spacing[axis1]
 		}
@@ -621,17 +820,18 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 
 func (g *GridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
 	grid := g.computeLayout(widget, ctx)
+	padding := widget.Padding()
 
-	w := g.margin*2 + maxI(len(grid[0])-1, 0)*g.spacing[0]
+	w := g.margin*2 + padding.Horizontal() + maxI(len(grid[0])-1, 0)*g.spacing[0]
 	for _, v := range grid[0] {
 		w += v
 	}
-	h := g.margin*2 + maxI(len(grid[1])-1, 0)*g.spacing[1]
+	h := g.margin*2 + padding.Vertical() + maxI(len(grid[1])-1, 0)*g.spacing[1]
 	for _, v := range grid[1] {
 		h += v
 	}
 	if _, ok := widget.(*Window); ok {
-		h += widget.Theme().WindowHeaderHeight - g.margin/2
+		h += HeaderHeight(widget) - g.margin/2
 	}
 	return w, h
 }
@@ -642,7 +842,7 @@ func (g *GridLayout) computeLayout(widget Widget, ctx *nanovgo.Context) [][]int
 	numChildren := widget.ChildCount()
 	visibleChildren := 0
 	for _, child := range widget.Children() {
-		if child.Visible() {
+		if isLaidOut(child) {
 			visibleChildren++
 		}
 	}
@@ -665,16 +865,11 @@ func (g *GridLayout) computeLayout(widget Widget, ctx *nanovgo.Context) [][]int
 				}
 				w = children[child]
 				child++
-				if w.Visible() {
+				if isLaidOut(w) {
 					break
 				}
 			}
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			targetSize := []int{
-				toI(fw > 0, fw, pw),
-				toI(fh > 0, fh, ph),
-			}
+			targetSize := OuterSize(w, ctx)
 			grid[axis1][i1] = maxI(grid[axis1][i1], targetSize[axis1])
 			grid[axis2][i2] = maxI(grid[axis2][i2], targetSize[axis2])
 		}
@@ -830,52 +1025,45 @@ func (a *AdvancedGridLayout) Anchor(widget Widget) Anchor {
 
 func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 	grid := a.computeLayout(widget, ctx)
-	grid[0] = append([]int{a.margin}, grid[0]...)
+	padding := widget.Padding()
+	grid[0] = append([]int{a.margin + padding.Left}, grid[0]...)
 	if _, ok := widget.(*Window); ok {
-		grid[1] = append([]int{widget.Theme().WindowHeaderHeight + a.margin/2}, grid[1]...)
+		grid[1] = append([]int{HeaderHeight(widget) + a.margin/2 + padding.Top}, grid[1]...)
 	} else {
-		grid[1] = append([]int{a.margin}, grid[1]...)
+		grid[1] = append([]int{a.margin + padding.Top}, grid[1]...)
 	}
 	for axis := 0; axis < 2; axis++ {
 		for i := 1; i < len(grid[axis]); i++ {
 			grid[axis][i] += grid[axis][i-1]
 		}
 		for _, w := range widget.Children() {
-			if !w.Visible() {
+			if !isLaidOut(w) {
 				continue
 			}
 			anchor := a.Anchor(w)
 			itemPos := grid[axis][anchor.pos[axis]]
 			cellSize := grid[axis][anchor.pos[axis]+anchor.size[axis]] - itemPos
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			var targetSize int
-			if axis == 0 {
-				targetSize = toI(fw > 0, fw, pw)
-			} else {
-				targetSize = toI(fh > 0, fh, ph)
-			}
-			switch anchor.align[axis] {
+			hint := OuterSize(w, ctx)
+			targetSize := hint[axis]
+			switch ChildAlignment(w, axis, anchor.align[axis]) {
 			case Minimum:
 			case Middle:
 				itemPos += (cellSize - targetSize) / 2
 			case Maximum:
 				itemPos += cellSize - targetSize
 			case Fill:
-				if axis == 0 {
-					targetSize = toI(fw > 0, fw, cellSize)
-				} else {
-					targetSize = toI(fh > 0, fh, cellSize)
-				}
+				targetSize = fitOuter(w, axis, cellSize, targetSize)
 			}
+			// leave out the margin of the child on this axis
+			margin := w.Margin()
 			posX, posY := w.Position()
 			sizeW, sizeH := w.Size()
 			if axis == 0 {
-				posX = itemPos
-				sizeW = targetSize
+				posX = itemPos + margin.Left
+				sizeW = maxI(targetSize-margin.Horizontal(), 0)
 			} else {
-				posY = itemPos
-				sizeH = targetSize
+				posY = itemPos + margin.Top
+				sizeH = maxI(targetSize-margin.Vertical(), 0)
 			}
 			w.SetPosition(posX, posY)
 			w.SetSize(sizeW, sizeH)
@@ -886,8 +1074,9 @@ func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context
 
 func (a *AdvancedGridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
 	grid := a.computeLayout(widget, ctx)
-	sizeW := a.margin * 2
-	sizeH := a.margin * 2
+	padding := widget.Padding()
+	sizeW := a.margin*2 + padding.Horizontal()
+	sizeH := a.margin*2 + padding.Vertical()
 	for _, size := range grid[0] {
 		sizeW += size
 	}
@@ -895,7 +1084,7 @@ func (a *AdvancedGridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context)
 		sizeH += size
 	}
 	if _, ok := widget.(*Window); ok {
-		sizeH += widget.Theme().WindowHeaderHeight - a.margin/2
+		sizeH += HeaderHeight(widget) - a.margin/2
 	}
 	return sizeW, sizeH
 }
@@ -906,11 +1095,12 @@ func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context)
 	containerW := toI(fw > 0, fw, widget.Width())
 	containerH := toI(fh > 0, fh, widget.Height())
 
-	extraX := 2 * a.margin
-	extraY := 2 * a.margin
+	padding := widget.Padding()
+	extraX := 2*a.margin + padding.Horizontal()
+	extraY := 2*a.margin + padding.Vertical()
 
 	if _, ok := widget.(*Window); ok {
-		extraY += widget.Theme().WindowHeaderHeight - a.margin/2
+		extraY += HeaderHeight(widget) - a.margin/2
 	}
 
 	containerW -= extraX
@@ -932,17 +1122,13 @@ func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context)
 
 		for phase := 0; phase < 2; phase++ {
 			for widget, anchor := range a.anchors {
-				if !widget.Visible() {
+				if !isLaidOut(widget) {
 					continue
 				}
 				if (anchor.size[axis]) == 1 != (phase == 0) {
 					continue
 				}
-				pw, ph := widget.PreferredSize(widget, ctx)
-				ps := toI(axis == 0, pw, ph)
-				fw, fh := widget.FixedSize()
-				fs := toI(axis == 0, fw, fh)
-				targetSize := toI(fs > 0, fs, ps)
+				targetSize := OuterSize(widget, ctx)[axis]
 				if int(anchor.pos[axis])+int(anchor.size[axis]) > len(grid) {
 					panic("Advanced grid layout: widget is out of bounds: " + anchor.String())
 				}
@@ -0,0 +1,575 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+	"sort"
+)
+
+// ListDataSource provides the rows displayed by a ListView
+//
+// Only the rows that are currently visible exist as widgets: CreateItem() is
+// called to build a row widget (created as a child of the list) and the
+// widgets are recycled while scrolling, calling UpdateItem() to bind them to
+// a different index.
+type ListDataSource interface {
+	ItemCount() int
+	CreateItem(list *ListView) Widget
+	UpdateItem(item Widget, index int)
+}
+
+// StringListSource is a ListDataSource showing a slice of strings as labels
+type StringListSource struct {
+	Items []string
+}
+
+func NewStringListSource(items []string) *StringListSource {
+	return &StringListSource{
+		Items: items,
+	}
+}
+
+func (s *StringListSource) ItemCount() int {
+	return len(s.Items)
+}
+
+func (s *StringListSource) CreateItem(list *ListView) Widget {
+	label := NewLabel(list, "")
+	label.SetFont(list.Theme().FontNormal)
+	return label
+}
+
+func (s *StringListSource) UpdateItem(item Widget, index int) {
+	item.(*Label).SetCaption(s.Items[index])
+}
+
+type ListSelectionMode int
+
+const (
+	ListSelectionNone ListSelectionMode = iota
+	ListSelectionSingle
+	ListSelectionMultiple
+)
+
+const listItemMargin = 4
+
+// Virtualized list widget
+//
+// ListView shows the rows of a ListDataSource with a fixed row height. Only
+// the visible rows are laid out and drawn, so the list scales to a very
+// large number of items. Rows can be selected with the mouse (Shift extends
+// the selection, Ctrl toggles single rows) and the keyboard.
+type ListView struct {
+	WidgetImplement
+
+	source         ListDataSource
+	itemHeight     int
+	selectionMode  ListSelectionMode
+	selected       map[int]bool
+	anchor         int
+	current        int
+	hover          int
+	scrollPosition float32
+	items          []Widget
+	itemIndices    []int
+	dragScroll     bool
+	bindingItems   bool
+	callback       func([]int)
+}
+
+func NewListView(parent Widget, sources ...ListDataSource) *ListView {
+	var source ListDataSource
+	switch len(sources) {
+	case 0:
+	case 1:
+		source = sources[0]
+	default:
+		panic("NewListView can accept only one extra parameter (source)")
+	}
+	list := &ListView{
+		source:        source,
+		selectionMode: ListSelectionSingle,
+		selected:      make(map[int]bool),
+		anchor:        -1,
+		current:       -1,
+		hover:         -1,
+	}
+	InitWidget(list, parent)
+	return list
+}
+
+// DataSource() returns the data source of the list
+func (l *ListView) DataSource() ListDataSource {
+	return l.source
+}
+
+// SetDataSource() sets the data source of the list; the selection is cleared
+func (l *ListView) SetDataSource(source ListDataSource) {
+	for _, item := range l.items {
+		l.RemoveChild(item)
+	}
+	l.items = nil
+	l.itemIndices = nil
+	l.source = source
+	l.scrollPosition = 0
+	l.current = -1
+	l.anchor = -1
+	if len(l.selected) > 0 {
+		l.selected = make(map[int]bool)
+		l.notifySelection()
+	}
+}
+
+// Refresh() rebinds all the visible rows; call it after the data of the source changed
+func (l *ListView) Refresh() {
+	for i := range l.itemIndices {
+		l.itemIndices[i] = -1
+	}
+	count := l.ItemCount()
+	changed := false
+	for index := range l.selected {
+		if index >= count {
+			delete(l.selected, index)
+			changed = true
+		}
+	}
+	l.current = minI(l.current, count-1)
+	if changed {
+		l.notifySelection()
+	}
+}
+
+// ItemCount() returns the number of rows of the data source
+func (l *ListView) ItemCount() int {
+	if l.source == nil {
+		return 0
+	}
+	return l.source.ItemCount()
+}
+
+// ItemHeight() returns the height of a row
+func (l *ListView) ItemHeight() int {
+	if l.itemHeight > 0 {
+		return l.itemHeight
+	}
+	return l.FontSize() + 8
+}
+
+// SetItemHeight() sets the height of a row (0: derived from the font size)
+func (l *ListView) SetItemHeight(h int) {
+	l.itemHeight = h
+}
+
+// SelectionMode() returns how many rows can be selected
+func (l *ListView) SelectionMode() ListSelectionMode {
+	return l.selectionMode
+}
+
+// SetSelectionMode() sets how many rows can be selected
+func (l *ListView) SetSelectionMode(mode ListSelectionMode) {
+	l.selectionMode = mode
+	if mode == ListSelectionNone {
+		l.ClearSelection()
+	} else if mode == ListSelectionSingle && len(l.selected) > 1 {
+		l.SetSelection(l.current)
+	}
+}
+
+// Selection() returns the indices of the selected rows in ascending order
+func (l *ListView) Selection() []int {
+	selection := make([]int, 0, len(l.selected))
+	for index := range l.selected {
+		selection = append(selection, index)
+	}
+	sort.Ints(selection)
+	return selection
+}
+
+// SetSelection() replaces the selection; the last index becomes the current row
+func (l *ListView) SetSelection(indices ...int) {
+	if l.selectionMode == ListSelectionNone {
+		return
+	}
+	if l.selectionMode == ListSelectionSingle && len(indices) > 1 {
+		indices = indices[len(indices)-1:]
+	}
+	l.selected = make(map[int]bool)
+	count := l.ItemCount()
+	for _, index := range indices {
+		if index >= 0 && index < count {
+			l.selected[index] = true
+			l.current = index
+			l.anchor = index
+		}
+	}
+	l.notifySelection()
+}
+
+// ClearSelection() deselects all the rows
+func (l *ListView) ClearSelection() {
+	if len(l.selected) > 0 {
+		l.selected = make(map[int]bool)
+		l.notifySelection()
+	}
+}
+
+// IsSelected() returns whether the row at the given index is selected
+func (l *ListView) IsSelected(index int) bool {
+	return l.selected[index]
+}
+
+// CurrentIndex() returns the row which has the keyboard focus (-1 if none)
+func (l *ListView) CurrentIndex() int {
+	return l.current
+}
+
+// SetSelectionCallback() sets the callback invoked with the selected indices when the selection changes
+func (l *ListView) SetSelectionCallback(callback func(selection []int)) {
+	l.callback = callback
+}
+
+// EnsureVisible() scrolls the list so that the row at the given index is visible
+func (l *ListView) EnsureVisible(index int) {
+	rowH := float32(l.ItemHeight())
+	top := float32(index) * rowH
+	if top < l.scrollPosition {
+		l.setScrollPosition(top)
+	} else if top+rowH > l.scrollPosition+float32(l.h) {
+		l.setScrollPosition(top + rowH - float32(l.h))
+	}
+}
+
+// IndexAt() returns the row index at the given position (parent coordinates) or -1
+func (l *ListView) IndexAt(x, y int) int {
+	if !l.Contains(x, y) {
+		return -1
+	}
+	index := int((float32(y-l.y) + l.scrollPosition) / float32(l.ItemHeight()))
+	if index < 0 || index >= l.ItemCount() {
+		return -1
+	}
+	return index
+}
+
+func (l *ListView) notifySelection() {
+	if l.callback != nil {
+		l.callback(l.Selection())
+	}
+}
+
+func (l *ListView) contentHeight() int {
+	return l.ItemCount() * l.ItemHeight()
+}
+
+func (l *ListView) setScrollPosition(position float32) {
+	l.scrollPosition = clampF(position, 0.0, maxF(0.0, float32(l.contentHeight()-l.h)))
+}
+
+func (l *ListView) scrollBarVisible() bool {
+	return l.contentHeight() > l.h
+}
+
+// thumbRect returns the offset and length of the scroll bar thumb along its track
+func (l *ListView) thumbRect() (float32, float32) {
+	var scroll float32
+	if maxScroll := float32(l.contentHeight() - l.h); maxScroll > 0 {
+		scroll = l.scrollPosition / maxScroll
+	}
+	return scrollThumb(float32(l.h-8), float32(l.h), float32(l.contentHeight()), scroll)
+}
+
+func (l *ListView) overScrollBar(x, y int) bool {
+	return l.scrollBarVisible() && l.Contains(x, y) && x >= l.x+l.w-scrollBarSize
+}
+
+// selectIndex applies a click (or keyboard move) on a row to the selection
+func (l *ListView) selectIndex(index int, modifier glfw.ModifierKey) {
+	l.current = index
+	if l.selectionMode == ListSelectionNone {
+		return
+	}
+	ctrl := modifier&(glfw.ModControl|glfw.ModSuper) != 0
+	shift := modifier&glfw.ModShift != 0
+	if l.selectionMode == ListSelectionMultiple && shift && l.anchor != -1 {
+		if !ctrl {
+			l.selected = make(map[int]bool)
+		}
+		for i := minI(l.anchor, index); i <= maxI(l.anchor, index); i++ {
+			l.selected[i] = true
+		}
+	} else if l.selectionMode == ListSelectionMultiple && ctrl {
+		if l.selected[index] {
+			delete(l.selected, index)
+		} else {
+			l.selected[index] = true
+		}
+		l.anchor = index
+	} else {
+		if len(l.selected) == 1 && l.selected[index] {
+			l.anchor = index
+			return
+		}
+		l.selected = map[int]bool{index: true}
+		l.anchor = index
+	}
+	l.notifySelection()
+}
+
+// updateItems binds the recycled row widgets to the visible rows and places them
+func (l *ListView) updateItems(ctx *nanovgo.Context) {
+	if l.source == nil {
+		return
+	}
+	// binding and placing the rows doesn't invalidate the layout of the ancestors (see InvalidateLayout())
+	l.bindingItems = true
+	defer func() {
+		for _, item := range l.items {
+			clearLayoutDirty(item)
+		}
+		l.bindingItems = false
+	}()
+	count := l.source.ItemCount()
+	rowH := l.ItemHeight()
+	l.setScrollPosition(l.scrollPosition)
+
+	visible := minI(l.h/rowH+2, count)
+	if len(l.items) < visible {
+		for len(l.items) < visible {
+			l.items = append(l.items, l.source.CreateItem(l))
+		}
+		// row widgets are assigned to rows by index modulo the pool size
+		l.itemIndices = make([]int, len(l.items))
+		for i := range l.itemIndices {
+			l.itemIndices[i] = -1
+		}
+	}
+	if len(l.items) == 0 {
+		return
+	}
+	width := l.w - 2*listItemMargin
+	if l.scrollBarVisible() {
+		width -= scrollBarSize
+	}
+	first := int(l.scrollPosition) / rowH
+	last := minI(first+len(l.items), count)
+	for slot, item := range l.items {
+		index := first + (slot-first%len(l.items)+len(l.items))%len(l.items)
+		item.SetVisible(index < last)
+	}
+	for index := first; index < last; index++ {
+		slot := index % len(l.items)
+		item := l.items[slot]
+		item.SetPosition(listItemMargin, index*rowH-int(l.scrollPosition))
+		w, h := item.Size()
+		if l.itemIndices[slot] != index || w != width || h != rowH {
+			l.source.UpdateItem(item, index)
+			l.itemIndices[slot] = index
+			item.SetSize(width, rowH)
+			item.OnPerformLayout(item, ctx)
+		}
+	}
+}
+
+// InvalidateLayout() marks the list and its ancestors as needing a new layout, unless the change comes from binding the rows
+func (l *ListView) InvalidateLayout() {
+	if !l.bindingItems {
+		l.WidgetImplement.InvalidateLayout()
+	}
+}
+
+func (l *ListView) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	for i := range l.itemIndices {
+		l.itemIndices[i] = -1
+	}
+	l.updateItems(ctx)
+}
+
+func (l *ListView) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	width := 100
+	for _, item := range l.items {
+		if item.Visible() {
+			w, _ := item.PreferredSize(item, ctx)
+			width = maxI(width, w+2*listItemMargin)
+		}
+	}
+	return width + scrollBarSize, l.ItemHeight() * clampI(l.ItemCount(), 1, 10)
+}
+
+func (l *ListView) FindWidget(self Widget, x, y int) Widget {
+	if l.overScrollBar(x, y) {
+		return self
+	}
+	return l.WidgetImplement.FindWidget(self, x, y)
+}
+
+func (l *ListView) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 {
+		if !down && l.dragScroll {
+			l.dragScroll = false
+			return true
+		}
+		if down && l.overScrollBar(x, y) {
+			l.dragScroll = true
+			return true
+		}
+	}
+	if !l.enabled {
+		return false
+	}
+	if l.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier) {
+		return true
+	}
+	if button == glfw.MouseButton1 && down {
+		if index := l.IndexAt(x, y); index != -1 {
+			l.selectIndex(index, modifier)
+		}
+		return true
+	}
+	return false
+}
+
+func (l *ListView) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	l.hover = l.IndexAt(x, y)
+	return l.WidgetImplement.MouseMotionEvent(self, x, y, relX, relY, button, modifier)
+}
+
+func (l *ListView) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	l.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter {
+		l.hover = -1
+	}
+	return false
+}
+
+func (l *ListView) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if !l.dragScroll {
+		return false
+	}
+	_, size := l.thumbRect()
+	if track := float32(l.h-8) - size; track > 0 {
+		l.setScrollPosition(l.scrollPosition + float32(relY)*float32(l.contentHeight()-l.h)/track)
+	}
+	return true
+}
+
+func (l *ListView) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	if !l.scrollBarVisible() {
+		return false
+	}
+	l.setScrollPosition(l.scrollPosition - float32(relY)*2)
+	l.hover = l.IndexAt(x, y)
+	return true
+}
+
+func (l *ListView) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	count := l.ItemCount()
+	if !l.enabled || !l.focused || count == 0 || (action != glfw.Press && action != glfw.Repeat) {
+		return false
+	}
+	ctrl := modifier&(glfw.ModControl|glfw.ModSuper) != 0
+	index := l.current
+	page := maxI(1, l.h/l.ItemHeight()-1)
+	switch key {
+	case glfw.KeyUp:
+		index--
+	case glfw.KeyDown:
+		index++
+	case glfw.KeyPageUp:
+		index -= page
+	case glfw.KeyPageDown:
+		index += page
+	case glfw.KeyHome:
+		index = 0
+	case glfw.KeyEnd:
+		index = count - 1
+	case glfw.KeySpace:
+		if l.current != -1 {
+			l.selectIndex(l.current, modifier)
+		}
+		return true
+	case glfw.KeyA:
+		if ctrl && l.selectionMode == ListSelectionMultiple {
+			l.selected = make(map[int]bool, count)
+			for i := 0; i < count; i++ {
+				l.selected[i] = true
+			}
+			l.notifySelection()
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+	index = clampI(index, 0, count-1)
+	if ctrl && modifier&glfw.ModShift == 0 {
+		// Ctrl moves the current row without changing the selection
+		l.current = index
+	} else {
+		l.selectIndex(index, modifier)
+	}
+	l.EnsureVisible(index)
+	return true
+}
+
+func (l *ListView) Draw(self Widget, ctx *nanovgo.Context) {
+	l.updateItems(ctx)
+
+	x := float32(l.x)
+	y := float32(l.y)
+	w := float32(l.w)
+	h := float32(l.h)
+
+	bg := nanovgo.BoxGradient(x+1, y+1, w-2, h-2, 3, 4, nanovgo.MONO(0, 32), nanovgo.MONO(0, 64))
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, h, 3)
+	ctx.SetFillPaint(bg)
+	ctx.Fill()
+
+	ctx.Save()
+	ctx.IntersectScissor(x, y, w, h)
+	rowH := l.ItemHeight()
+	rowW := w
+	if l.scrollBarVisible() {
+		rowW -= scrollBarSize
+	}
+	first := int(l.scrollPosition) / rowH
+	last := minI(l.ItemCount(), (int(l.scrollPosition)+l.h)/rowH+1)
+	for index := first; index < last; index++ {
+		rowY := y + float32(index*rowH) - l.scrollPosition
+		drawItemBackground(ctx, x, rowY, rowW, float32(rowH), l.selected[index], index == l.hover && l.enabled,
+			index == l.current && l.focused)
+	}
+	l.WidgetImplement.Draw(self, ctx)
+	ctx.Restore()
+
+	if l.scrollBarVisible() {
+		offset, size := l.thumbRect()
+		drawScrollBar(ctx, false, x+w-scrollBarSize, y+4, 8, h-8, offset, size)
+	}
+}
+
+func (l *ListView) String() string {
+	return l.StringHelper("ListView", fmt.Sprintf("%d items", l.ItemCount()))
+}
+
+// drawItemBackground draws the highlight of a row of a list-like widget
+func drawItemBackground(ctx *nanovgo.Context, x, y, w, h float32, selected, hover, current bool) {
+	if selected || hover {
+		ctx.BeginPath()
+		ctx.Rect(x, y, w, h)
+		if selected {
+			ctx.SetFillColor(nanovgo.MONO(255, 48))
+		} else {
+			ctx.SetFillColor(nanovgo.MONO(255, 16))
+		}
+		ctx.Fill()
+	}
+	if current {
+		ctx.BeginPath()
+		ctx.Rect(x+0.5, y+0.5, w-1, h-1)
+		ctx.SetStrokeWidth(1.0)
+		ctx.SetStrokeColor(nanovgo.MONO(255, 80))
+		ctx.Stroke()
+	}
+}
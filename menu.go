@@ -0,0 +1,725 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MenuItemType tells how a menu item behaves when it is activated
+type MenuItemType int
+
+const (
+	MenuItemNormal MenuItemType = iota
+	MenuItemCheck
+	MenuItemRadio
+	MenuItemSeparator
+)
+
+func (t MenuItemType) String() string {
+	switch t {
+	case MenuItemNormal:
+		return "Normal"
+	case MenuItemCheck:
+		return "Check"
+	case MenuItemRadio:
+		return "Radio"
+	case MenuItemSeparator:
+		return "Separator"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// MenuItem is an entry of a Menu
+//
+// The character following a '&' in the label is the mnemonic of the item: it
+// is underlined, and typing it while the menu is open activates the item
+// ("&&" shows a plain '&').
+type MenuItem struct {
+	menu           *Menu
+	itemType       MenuItemType
+	label          string
+	shortcut       string
+	icon           Icon
+	checked        bool
+	enabled        bool
+	group          int
+	subMenu        *Menu
+	callback       func()
+	changeCallback func(checked bool)
+}
+
+func (i *MenuItem) Type() MenuItemType {
+	return i.itemType
+}
+
+func (i *MenuItem) Label() string {
+	return i.label
+}
+
+func (i *MenuItem) SetLabel(label string) {
+	i.label = label
+}
+
+// Mnemonic() returns the (lower case) mnemonic character of the item, or 0
+func (i *MenuItem) Mnemonic() rune {
+	text, index := parseMnemonic(i.label)
+	if index < 0 {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(text[index:])
+	return unicode.ToLower(r)
+}
+
+// Shortcut() returns the text describing the keyboard shortcut of the item
+func (i *MenuItem) Shortcut() string {
+	return i.shortcut
+}
+
+// SetShortcut() sets the text describing the keyboard shortcut of the item (e.g. "Ctrl+S"); it is only displayed
+func (i *MenuItem) SetShortcut(shortcut string) {
+	i.shortcut = shortcut
+}
+
+func (i *MenuItem) Icon() Icon {
+	return i.icon
+}
+
+// SetIcon() sets the icon shown before the label when the item is not checked
+func (i *MenuItem) SetIcon(icon Icon) {
+	i.icon = icon
+}
+
+func (i *MenuItem) Enabled() bool {
+	return i.enabled
+}
+
+func (i *MenuItem) SetEnabled(enabled bool) {
+	i.enabled = enabled
+}
+
+func (i *MenuItem) Checked() bool {
+	return i.checked
+}
+
+// SetChecked() checks or unchecks the item; checking a radio item unchecks the other items of its group
+func (i *MenuItem) SetChecked(checked bool) {
+	if i.itemType == MenuItemRadio && checked && i.menu != nil {
+		for _, item := range i.menu.items {
+			if item != i && item.itemType == MenuItemRadio && item.group == i.group && item.checked {
+				item.checked = false
+				if item.changeCallback != nil {
+					item.changeCallback(false)
+				}
+			}
+		}
+	}
+	if i.checked == checked {
+		return
+	}
+	i.checked = checked
+	if i.changeCallback != nil {
+		i.changeCallback(checked)
+	}
+}
+
+// Group() returns the radio group of the item
+func (i *MenuItem) Group() int {
+	return i.group
+}
+
+// SubMenu() returns the menu opened by the item, or nil
+func (i *MenuItem) SubMenu() *Menu {
+	return i.subMenu
+}
+
+// SetCallback() sets the callback invoked when the item is activated
+func (i *MenuItem) SetCallback(callback func()) {
+	i.callback = callback
+}
+
+// SetChangeCallback() sets the callback invoked when a check or radio item is checked or unchecked
+func (i *MenuItem) SetChangeCallback(callback func(checked bool)) {
+	i.changeCallback = callback
+}
+
+// Menu is a list of items opened over the other widgets
+//
+// A menu is a Popup placed in screen coordinates: it is shown with Open() and
+// hidden when an item is activated, when Escape is pressed or when the mouse
+// is pressed outside of it. The items are navigated with the arrow keys and
+// their mnemonics; sub-menus open on hover or with the right arrow key.
+type Menu struct {
+	Popup
+
+	items        []*MenuItem
+	highlight    int
+	parentMenu   *Menu
+	openSubMenu  *Menu
+	owner        Widget
+	restoreFocus Widget
+	closeHandler func()
+}
+
+// NewMenu() creates a hidden menu; the menu is added to the screen the parent belongs to
+func NewMenu(parent Widget) *Menu {
+	screen := findScreen(parent)
+	if screen == nil {
+		panic("NewMenu: the parent must belong to a Screen")
+	}
+	menu := &Menu{
+		highlight: -1,
+	}
+	if _, ok := parent.(*Screen); !ok {
+		menu.parentWindow = parent.FindWindow()
+	}
+	InitWidget(menu, screen)
+	menu.SetVisible(false)
+	return menu
+}
+
+// AddItem() appends an item invoking the callback (which may be nil) when activated
+func (m *Menu) AddItem(label string, callback func()) *MenuItem {
+	item := m.addItem(MenuItemNormal, label)
+	item.callback = callback
+	return item
+}
+
+// AddCheckItem() appends an item toggled when activated
+func (m *Menu) AddCheckItem(label string, checked bool) *MenuItem {
+	item := m.addItem(MenuItemCheck, label)
+	item.checked = checked
+	return item
+}
+
+// AddRadioItem() appends an item checked when activated; checking it unchecks the other radio items of the same group
+func (m *Menu) AddRadioItem(label string, group int) *MenuItem {
+	item := m.addItem(MenuItemRadio, label)
+	item.group = group
+	return item
+}
+
+// AddSeparator() appends a line separating groups of items
+func (m *Menu) AddSeparator() {
+	m.addItem(MenuItemSeparator, "")
+}
+
+// AddSubMenu() appends an item opening a nested menu, and returns the nested menu
+func (m *Menu) AddSubMenu(label string) *Menu {
+	subMenu := NewMenu(m)
+	subMenu.parentMenu = m
+	item := m.addItem(MenuItemNormal, label)
+	item.subMenu = subMenu
+	return subMenu
+}
+
+func (m *Menu) addItem(itemType MenuItemType, label string) *MenuItem {
+	item := &MenuItem{
+		menu:     m,
+		itemType: itemType,
+		label:    label,
+		enabled:  itemType != MenuItemSeparator,
+	}
+	m.items = append(m.items, item)
+	return item
+}
+
+func (m *Menu) Items() []*MenuItem {
+	return m.items
+}
+
+// Clear() removes all the items
+func (m *Menu) Clear() {
+	m.closeSubMenu()
+	for _, item := range m.items {
+		if item.subMenu != nil {
+			m.parent.RemoveChild(item.subMenu)
+		}
+	}
+	m.items = nil
+	m.highlight = -1
+}
+
+// ParentMenu() returns the menu this menu is nested in, or nil
+func (m *Menu) ParentMenu() *Menu {
+	return m.parentMenu
+}
+
+// NewContextMenu() creates a menu opened by right-clicking the widget (or pressing the Menu key while it has the focus)
+func NewContextMenu(widget Widget) *Menu {
+	menu := NewMenu(widget)
+	widget.SetContextMenu(menu)
+	return menu
+}
+
+// Open() shows the menu with its top left corner at the given screen position, moving it to stay inside the screen
+func (m *Menu) Open(x, y int) {
+	m.Close()
+	screen := findScreen(m)
+	if len(screen.focusPath) > 1 {
+		m.restoreFocus = screen.focusPath[0]
+	}
+	m.open(x, y, x, y)
+	m.RequestFocus(m)
+}
+
+// open() shows the menu at the given screen position; when it doesn't fit on the right (or below),
+// it ends at flipX (or flipY) instead, and in any case it is kept inside the screen
+func (m *Menu) open(x, y, flipX, flipY int) {
+	screen := findScreen(m)
+	w, h := m.PreferredSize(m, screen.NVGContext())
+	sw, sh := screen.Size()
+	if x+w > sw {
+		x = flipX - w
+	}
+	if y+h > sh {
+		y = flipY - h
+	}
+	m.SetPosition(clampI(x, 0, maxI(sw-w, 0)), clampI(y, 0, maxI(sh-h, 0)))
+	m.SetSize(w, h)
+	m.highlight = -1
+	m.SetVisible(true)
+	screen.MoveWindowToFront(m)
+}
+
+// Close() hides the menu and its open sub-menus; closing a top level menu gives the focus back
+func (m *Menu) Close() {
+	if !m.visible {
+		return
+	}
+	m.closeSubMenu()
+	m.SetVisible(false)
+	m.highlight = -1
+	if m.parentMenu != nil {
+		return
+	}
+	restore := m.restoreFocus
+	m.restoreFocus = nil
+	if restore != nil && findScreen(restore) != nil && restore.VisibleRecursive() {
+		restore.RequestFocus(restore)
+	} else if screen := findScreen(m); screen != nil {
+		screen.UpdateFocus(nil)
+	}
+	if m.closeHandler != nil {
+		m.closeHandler()
+	}
+}
+
+// closeAll() closes the top level menu this menu is nested in
+func (m *Menu) closeAll() {
+	root := m
+	for root.parentMenu != nil {
+		root = root.parentMenu
+	}
+	root.Close()
+}
+
+func (m *Menu) closeSubMenu() {
+	if m.openSubMenu != nil {
+		m.openSubMenu.Close()
+		m.openSubMenu = nil
+	}
+}
+
+// showSubMenu() opens the sub-menu of an item next to it; with the keyboard the sub-menu gets the focus
+func (m *Menu) showSubMenu(index int, keyboard bool) {
+	item := m.items[index]
+	if item.subMenu == nil || !item.enabled {
+		m.closeSubMenu()
+		return
+	}
+	if m.openSubMenu != item.subMenu {
+		m.closeSubMenu()
+		padding, _, _ := m.metrics()
+		top := m.y + m.itemTop(index)
+		item.subMenu.open(m.x+m.w, top-padding, m.x, top+m.itemHeight(item)+padding)
+		m.openSubMenu = item.subMenu
+	}
+	if keyboard {
+		item.subMenu.RequestFocus(item.subMenu)
+		item.subMenu.moveHighlight(1)
+	}
+}
+
+// activate() performs the action of an item: it opens its sub-menu, or closes the menus and invokes its callback
+func (m *Menu) activate(index int) {
+	item := m.items[index]
+	if !item.enabled {
+		return
+	}
+	if item.subMenu != nil {
+		m.showSubMenu(index, true)
+		return
+	}
+	switch item.itemType {
+	case MenuItemCheck:
+		item.SetChecked(!item.checked)
+	case MenuItemRadio:
+		item.SetChecked(true)
+	}
+	m.closeAll()
+	if item.callback != nil {
+		item.callback()
+	}
+}
+
+// moveHighlight() highlights the next (or previous) enabled item, wrapping around
+func (m *Menu) moveHighlight(step int) {
+	count := len(m.items)
+	index := m.highlight
+	if index < 0 && step < 0 {
+		index = count
+	}
+	for i := 0; i < count; i++ {
+		index = (index + step + count) % count
+		if m.items[index].enabled {
+			m.highlight = index
+			m.RequestRedraw()
+			return
+		}
+	}
+}
+
+// menuBar() returns the menu bar the top level menu belongs to, or nil
+func (m *Menu) menuBar() *MenuBar {
+	root := m
+	for root.parentMenu != nil {
+		root = root.parentMenu
+	}
+	bar, _ := root.owner.(*MenuBar)
+	return bar
+}
+
+// contains() returns whether the menu or one of its open sub-menus contains the screen position
+func (m *Menu) contains(x, y int) bool {
+	for menu := m; menu != nil; menu = menu.openSubMenu {
+		if menu.Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// metrics() returns the padding around the items and the heights of items and separators
+func (m *Menu) metrics() (padding, itemHeight, separatorHeight int) {
+	fontSize := float32(m.FontSize())
+	return 4, int(fontSize * 1.5), int(fontSize * 0.5)
+}
+
+func (m *Menu) itemHeight(item *MenuItem) int {
+	_, itemHeight, separatorHeight := m.metrics()
+	if item.itemType == MenuItemSeparator {
+		return separatorHeight
+	}
+	return itemHeight
+}
+
+// itemTop() returns the vertical position of an item, relative to the menu
+func (m *Menu) itemTop(index int) int {
+	y, _, _ := m.metrics()
+	for _, item := range m.items[:index] {
+		y += m.itemHeight(item)
+	}
+	return y
+}
+
+// itemAt() returns the item at the vertical position y (relative to the menu), or -1
+func (m *Menu) itemAt(y int) int {
+	top, _, _ := m.metrics()
+	for i, item := range m.items {
+		h := m.itemHeight(item)
+		if y >= top && y < top+h {
+			if item.itemType == MenuItemSeparator {
+				return -1
+			}
+			return i
+		}
+		top += h
+	}
+	return -1
+}
+
+// RefreshRelativePlacement() does nothing: menus are placed in screen coordinates when opened
+func (m *Menu) RefreshRelativePlacement() {
+}
+
+func (m *Menu) FindWindow() IWindow {
+	return m
+}
+
+func (m *Menu) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+}
+
+func (m *Menu) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	fontSize := float32(m.FontSize())
+	padding, _, _ := m.metrics()
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(m.theme.FontNormal)
+	labelWidth, shortcutWidth := float32(0), float32(0)
+	h := 2 * padding
+	for _, item := range m.items {
+		h += m.itemHeight(item)
+		text, _ := parseMnemonic(item.label)
+		tw, _ := ctx.TextBounds(0, 0, text)
+		labelWidth = maxF(labelWidth, tw)
+		if item.shortcut != "" {
+			sw, _ := ctx.TextBounds(0, 0, item.shortcut)
+			shortcutWidth = maxF(shortcutWidth, sw)
+		}
+	}
+	w := labelWidth + fontSize*2.5 + float32(2*padding)
+	if shortcutWidth > 0 {
+		w += fontSize*2 + shortcutWidth
+	}
+	return maxI(int(w), 120), h
+}
+
+func (m *Menu) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if down || (button != glfw.MouseButton1 && button != glfw.MouseButton2) {
+		return true
+	}
+	if m.Contains(x, y) {
+		if index := m.itemAt(y - m.y); index >= 0 {
+			m.activate(index)
+		}
+	}
+	return true
+}
+
+func (m *Menu) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	return m.MouseMotionEvent(self, x, y, relX, relY, button, modifier)
+}
+
+func (m *Menu) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	index := -1
+	if m.Contains(x, y) {
+		index = m.itemAt(y - m.y)
+	}
+	if index < 0 && m.openSubMenu != nil {
+		// keep the item of the open sub-menu highlighted while the mouse moves towards it
+		return true
+	}
+	if index != m.highlight {
+		m.highlight = index
+		if index >= 0 {
+			m.showSubMenu(index, false)
+		}
+		m.RequestRedraw()
+	}
+	return true
+}
+
+func (m *Menu) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if action == glfw.Release || len(m.items) == 0 && key != glfw.KeyEscape {
+		return false
+	}
+	switch key {
+	case glfw.KeyDown:
+		m.moveHighlight(1)
+	case glfw.KeyUp:
+		m.moveHighlight(-1)
+	case glfw.KeyRight:
+		if m.highlight >= 0 && m.items[m.highlight].subMenu != nil {
+			m.showSubMenu(m.highlight, true)
+		} else if bar := m.menuBar(); bar != nil {
+			bar.openAdjacentMenu(1)
+		}
+	case glfw.KeyLeft:
+		if m.parentMenu != nil {
+			m.parentMenu.closeSubMenu()
+			m.parentMenu.RequestFocus(m.parentMenu)
+		} else if bar := m.menuBar(); bar != nil {
+			bar.openAdjacentMenu(-1)
+		}
+	case glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeySpace:
+		if m.highlight >= 0 {
+			m.activate(m.highlight)
+		}
+	case glfw.KeyEscape:
+		if m.parentMenu != nil {
+			m.parentMenu.closeSubMenu()
+			m.parentMenu.RequestFocus(m.parentMenu)
+		} else {
+			m.Close()
+		}
+	default:
+		return false
+	}
+	m.RequestRedraw()
+	return true
+}
+
+func (m *Menu) KeyboardCharacterEvent(self Widget, codePoint rune) bool {
+	codePoint = unicode.ToLower(codePoint)
+	for i, item := range m.items {
+		if item.enabled && item.Mnemonic() == codePoint {
+			m.highlight = i
+			m.activate(i)
+			m.RequestRedraw()
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Menu) Draw(self Widget, ctx *nanovgo.Context) {
+	if !m.visible {
+		return
+	}
+	ds := float32(m.theme.WindowDropShadowSize)
+	cr := float32(m.theme.ButtonCornerRadius)
+	x := float32(m.x)
+	y := float32(m.y)
+	w := float32(m.w)
+	h := float32(m.h)
+	fontSize := float32(m.FontSize())
+	padding, _, _ := m.metrics()
+	pad := float32(padding)
+
+	shadowPaint := nanovgo.BoxGradient(x, y, w, h, cr*2, ds*2, m.theme.DropShadow, m.theme.Transparent)
+	ctx.BeginPath()
+	ctx.Rect(x-ds, y-ds, w+ds*2, h+ds*2)
+	ctx.RoundedRect(x, y, w, h, cr)
+	ctx.PathWinding(nanovgo.Hole)
+	ctx.SetFillPaint(shadowPaint)
+	ctx.Fill()
+
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, h, cr)
+	ctx.SetFillColor(m.theme.WindowPopup)
+	ctx.Fill()
+
+	top := y + pad
+	for i, item := range m.items {
+		ih := float32(m.itemHeight(item))
+		if item.itemType == MenuItemSeparator {
+			ctx.BeginPath()
+			ctx.MoveTo(x+pad, top+ih*0.5)
+			ctx.LineTo(x+w-pad, top+ih*0.5)
+			ctx.SetStrokeColor(m.theme.BorderMedium)
+			ctx.Stroke()
+			top += ih
+			continue
+		}
+		if i == m.highlight {
+			ctx.BeginPath()
+			ctx.RoundedRect(x+pad, top, w-2*pad, ih, 3)
+			ctx.SetFillColor(nanovgo.MONO(255, 48))
+			ctx.Fill()
+		}
+		textColor := m.theme.TextColor
+		if !item.enabled {
+			textColor = m.theme.DisabledTextColor
+		}
+		middle := top + ih*0.5
+		ctx.SetFillColor(textColor)
+
+		var icon Icon
+		switch {
+		case item.checked && item.itemType == MenuItemCheck:
+			icon = IconCheck
+		case item.checked && item.itemType == MenuItemRadio:
+			ctx.BeginPath()
+			ctx.Circle(x+pad+fontSize*0.75, middle, fontSize*0.2)
+			ctx.Fill()
+		case !item.checked:
+			icon = item.icon
+		}
+		if icon != 0 {
+			ctx.SetFontSize(fontSize)
+			ctx.SetFontFace(m.theme.FontIcons)
+			ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+			ctx.TextRune(x+pad+fontSize*0.75, middle, []rune{rune(icon)})
+		}
+
+		ctx.SetFontSize(fontSize)
+		ctx.SetFontFace(m.theme.FontNormal)
+		drawMnemonicLabel(ctx, x+pad+fontSize*1.5, middle, fontSize, item.label)
+		if item.shortcut != "" {
+			ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
+			ctx.Text(x+w-pad-fontSize, middle, item.shortcut)
+		}
+		if item.subMenu != nil {
+			ctx.SetFontFace(m.theme.FontIcons)
+			ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+			ctx.TextRune(x+w-pad-fontSize*0.5, middle, []rune{rune(IconRightOpen)})
+		}
+		top += ih
+	}
+}
+
+func (m *Menu) String() string {
+	return m.StringHelper("Menu", "")
+}
+
+// openContextMenu opens at the screen position the context menu of the widget, or of its closest ancestor having one
+func openContextMenu(widget Widget, x, y int) bool {
+	for ; widget != nil; widget = widget.Parent() {
+		if menu := widget.ContextMenu(); menu != nil && widget.Enabled() {
+			menu.Open(x, y)
+			return true
+		}
+	}
+	return false
+}
+
+// closeMenusOutside closes the open top level menus when the mouse is pressed outside of
+// them and of the widget they were opened from (which handles the press itself)
+func closeMenusOutside(screen *Screen, x, y int) {
+	for _, child := range append([]Widget{}, screen.Children()...) {
+		menu, ok := child.(*Menu)
+		if !ok || !menu.visible || menu.parentMenu != nil || menu.contains(x, y) {
+			continue
+		}
+		if menu.owner != nil && menu.owner.VisibleRecursive() {
+			ox, oy := menu.owner.AbsolutePosition()
+			ow, oh := menu.owner.Size()
+			if x >= ox && y >= oy && x < ox+ow && y < oy+oh {
+				continue
+			}
+		}
+		menu.Close()
+	}
+}
+
+// parseMnemonic removes the '&' markers from a label and returns the byte index of the mnemonic character (or -1)
+func parseMnemonic(label string) (string, int) {
+	if !strings.ContainsRune(label, '&') {
+		return label, -1
+	}
+	var text strings.Builder
+	index := -1
+	runes := []rune(label)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '&' {
+			i++
+			if i == len(runes) {
+				break
+			}
+			if runes[i] != '&' && index < 0 {
+				index = text.Len()
+			}
+		}
+		text.WriteRune(runes[i])
+	}
+	return text.String(), index
+}
+
+// drawMnemonicLabel draws a label vertically centered at y, underlining its mnemonic character (the font must be set up)
+func drawMnemonicLabel(ctx *nanovgo.Context, x, y, fontSize float32, label string) {
+	text, index := parseMnemonic(label)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	ctx.Text(x, y, text)
+	if index < 0 {
+		return
+	}
+	_, size := utf8.DecodeRuneInString(text[index:])
+	before, _ := ctx.TextBounds(0, 0, text[:index])
+	width, _ := ctx.TextBounds(0, 0, text[index:index+size])
+	ctx.BeginPath()
+	ctx.Rect(x+before, y+fontSize*0.35, width, 1)
+	ctx.Fill()
+}
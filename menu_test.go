@@ -0,0 +1,28 @@
+package nanogui
+
+import "testing"
+
+func TestParseMnemonic(t *testing.T) {
+	tests := []struct {
+		label     string
+		wantText  string
+		wantIndex int
+	}{
+		{"", "", -1},
+		{"Plain", "Plain", -1},
+		{"&File", "File", 0},
+		{"Save &As...", "Save As...", 5},
+		{"Fish && Chips", "Fish & Chips", -1},
+		{"&&Save &Now", "&Save Now", 6},
+		{"&Open &Recent", "Open Recent", 0},
+		{"Trailing&", "Trailing", -1},
+		{"&", "", -1},
+		{"Ét&é", "Été", 3},
+	}
+	for _, test := range tests {
+		text, index := parseMnemonic(test.label)
+		if text != test.wantText || index != test.wantIndex {
+			t.Errorf("parseMnemonic(%q) = %q, %d, want %q, %d", test.label, text, index, test.wantText, test.wantIndex)
+		}
+	}
+}
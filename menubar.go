@@ -0,0 +1,326 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Menu bar widget
+//
+// MenuBar shows a row of menu titles spanning the top of a Screen, or of a
+// Window below its title. Clicking a title opens its drop-down menu; while a
+// menu is open, hovering the other titles (or pressing the left and right
+// arrow keys) switches menu. Alt and the mnemonic of a title (the character
+// following a '&') opens the menu from the keyboard.
+type MenuBar struct {
+	WidgetImplement
+
+	titles    []string
+	menus     []*Menu
+	positions []int
+	widths    []int
+	active    int
+	hover     int
+}
+
+// NewMenuBar() creates a menu bar attached to the top of a Screen or of a Window
+func NewMenuBar(parent Widget) *MenuBar {
+	bar := &MenuBar{
+		active: -1,
+		hover:  -1,
+	}
+	switch parent := parent.(type) {
+	case *Screen:
+	case *Window:
+		parent.menuBar = bar
+	default:
+		panic("NewMenuBar: the parent must be a Screen or a Window")
+	}
+	InitWidget(bar, parent)
+	return bar
+}
+
+// AddMenu() appends a title to the bar and returns the menu it opens
+func (b *MenuBar) AddMenu(title string) *Menu {
+	menu := NewMenu(b)
+	menu.owner = b
+	menu.closeHandler = func() {
+		if b.active >= 0 && b.menus[b.active] == menu {
+			b.active = -1
+			b.RequestRedraw()
+		}
+	}
+	b.titles = append(b.titles, title)
+	b.menus = append(b.menus, menu)
+	b.InvalidateLayout()
+	return menu
+}
+
+func (b *MenuBar) MenuCount() int {
+	return len(b.menus)
+}
+
+// Menu() returns the menu opened by the title at the given index
+func (b *MenuBar) Menu(index int) *Menu {
+	if index < 0 || index >= len(b.menus) {
+		return nil
+	}
+	return b.menus[index]
+}
+
+// Title() returns the title at the given index
+func (b *MenuBar) Title(index int) string {
+	if index < 0 || index >= len(b.titles) {
+		return ""
+	}
+	return b.titles[index]
+}
+
+func (b *MenuBar) SetTitle(index int, title string) {
+	if index < 0 || index >= len(b.titles) {
+		return
+	}
+	b.titles[index] = title
+	b.InvalidateLayout()
+}
+
+// ActiveMenu() returns the index of the open menu, or -1
+func (b *MenuBar) ActiveMenu() int {
+	return b.active
+}
+
+// BarHeight() returns the height of the bar
+func (b *MenuBar) BarHeight() int {
+	return int(float32(b.FontSize()) * 1.6)
+}
+
+// OpenMenu() opens the menu at the given index below its title
+func (b *MenuBar) OpenMenu(index int) {
+	if index < 0 || index >= len(b.menus) || len(b.positions) != len(b.menus) {
+		return
+	}
+	var restore Widget
+	if b.active >= 0 {
+		// the focus goes back where it was before the first menu was opened
+		previous := b.menus[b.active]
+		restore = previous.restoreFocus
+		previous.restoreFocus = nil
+		b.active = -1
+		previous.Close()
+	}
+	menu := b.menus[index]
+	if screen := findScreen(b); restore == nil && len(screen.focusPath) > 1 {
+		restore = screen.focusPath[0]
+	}
+	menu.restoreFocus = restore
+	ax, ay := b.AbsolutePosition()
+	x := ax + b.positions[index]
+	menu.open(x, ay+b.h, x, ay+b.h)
+	menu.RequestFocus(menu)
+	b.active = index
+	b.RequestRedraw()
+}
+
+// CloseMenu() closes the open menu, if any
+func (b *MenuBar) CloseMenu() {
+	if b.active >= 0 {
+		b.menus[b.active].Close()
+	}
+}
+
+// openAdjacentMenu() opens the menu next to the open one (step is 1 or -1), skipping the disabled menus
+func (b *MenuBar) openAdjacentMenu(step int) {
+	count := len(b.menus)
+	index := b.active
+	for i := 0; i < count; i++ {
+		index = (index + step + count) % count
+		if b.menus[index].Enabled() {
+			b.OpenMenu(index)
+			b.menus[index].moveHighlight(1)
+			return
+		}
+	}
+}
+
+// openMnemonic() opens the menu whose title has the given mnemonic character
+func (b *MenuBar) openMnemonic(r rune) bool {
+	for i, title := range b.titles {
+		text, index := parseMnemonic(title)
+		if index < 0 || !b.menus[i].Enabled() {
+			continue
+		}
+		if c, _ := utf8.DecodeRuneInString(text[index:]); unicode.ToLower(c) == r {
+			b.OpenMenu(i)
+			b.menus[i].moveHighlight(1)
+			return true
+		}
+	}
+	return false
+}
+
+// titleAt() returns the title at the horizontal position x (in parent coordinates), or -1
+func (b *MenuBar) titleAt(x int) int {
+	for i, position := range b.positions {
+		if x-b.x >= position && x-b.x < position+b.widths[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *MenuBar) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	fontSize := float32(b.FontSize())
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(b.theme.FontNormal)
+	b.positions = b.positions[:0]
+	b.widths = b.widths[:0]
+	x := 4
+	for _, title := range b.titles {
+		text, _ := parseMnemonic(title)
+		tw, _ := ctx.TextBounds(0, 0, text)
+		b.positions = append(b.positions, x)
+		b.widths = append(b.widths, int(tw+fontSize))
+		x += int(tw + fontSize)
+	}
+}
+
+func (b *MenuBar) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	if screen, ok := b.parent.(*Screen); ok {
+		return screen.w, b.BarHeight()
+	}
+	fontSize := float32(b.FontSize())
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(b.theme.FontNormal)
+	w := 8
+	for _, title := range b.titles {
+		text, _ := parseMnemonic(title)
+		tw, _ := ctx.TextBounds(0, 0, text)
+		w += int(tw + fontSize)
+	}
+	return w, b.BarHeight()
+}
+
+// IsPositionAbsolute() keeps the bar of a window out of its layout: the window places it below its title
+func (b *MenuBar) IsPositionAbsolute() bool {
+	_, ok := b.parent.(*Window)
+	return ok
+}
+
+func (b *MenuBar) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if !b.enabled || button != glfw.MouseButton1 {
+		return true
+	}
+	if down {
+		index := b.titleAt(x)
+		switch {
+		case index < 0:
+			b.CloseMenu()
+		case index == b.active:
+			b.CloseMenu()
+		case b.menus[index].Enabled():
+			b.OpenMenu(index)
+		}
+	}
+	return true
+}
+
+func (b *MenuBar) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	index := -1
+	if b.Contains(x, y) {
+		index = b.titleAt(x)
+	}
+	if index != b.hover {
+		b.hover = index
+		b.RequestRedraw()
+	}
+	if b.active >= 0 && index >= 0 && index != b.active && b.menus[index].Enabled() {
+		b.OpenMenu(index)
+	}
+	return index >= 0
+}
+
+func (b *MenuBar) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	b.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter && b.hover >= 0 {
+		b.hover = -1
+		b.RequestRedraw()
+	}
+	return false
+}
+
+func (b *MenuBar) Draw(self Widget, ctx *nanovgo.Context) {
+	x := float32(b.x)
+	y := float32(b.y)
+	w := float32(b.w)
+	h := float32(b.h)
+	fontSize := float32(b.FontSize())
+
+	ctx.BeginPath()
+	ctx.Rect(x, y, w, h)
+	ctx.SetFillPaint(nanovgo.LinearGradient(x, y, x, y+h, b.theme.ButtonGradientTopUnfocused, b.theme.ButtonGradientBotUnfocused))
+	ctx.Fill()
+	ctx.BeginPath()
+	ctx.MoveTo(x, y+h-0.5)
+	ctx.LineTo(x+w, y+h-0.5)
+	ctx.SetStrokeColor(b.theme.BorderDark)
+	ctx.Stroke()
+
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(b.theme.FontNormal)
+	for i, title := range b.titles {
+		if i >= len(b.positions) {
+			break
+		}
+		tx := x + float32(b.positions[i])
+		tw := float32(b.widths[i])
+		if i == b.active || (i == b.hover && b.menus[i].Enabled()) {
+			ctx.BeginPath()
+			ctx.RoundedRect(tx, y+2, tw, h-4, 3)
+			ctx.SetFillColor(nanovgo.MONO(255, toB(i == b.active, 48, 16)))
+			ctx.Fill()
+		}
+		if b.enabled && b.menus[i].Enabled() {
+			ctx.SetFillColor(b.theme.TextColor)
+		} else {
+			ctx.SetFillColor(b.theme.DisabledTextColor)
+		}
+		drawMnemonicLabel(ctx, tx+fontSize*0.5, y+h*0.5, fontSize, title)
+	}
+}
+
+func (b *MenuBar) String() string {
+	return b.StringHelper("MenuBar", "")
+}
+
+// openMenuBarMnemonic opens the menu of the focused window's menu bar (or of the screen menu bar)
+// whose title has the mnemonic of the key pressed with Alt
+func openMenuBarMnemonic(screen *Screen, key glfw.Key) bool {
+	var r rune
+	switch {
+	case key >= glfw.KeyA && key <= glfw.KeyZ:
+		r = 'a' + rune(key-glfw.KeyA)
+	case key >= glfw.Key0 && key <= glfw.Key9:
+		r = '0' + rune(key-glfw.Key0)
+	default:
+		return false
+	}
+	var bars []*MenuBar
+	if len(screen.focusPath) > 1 {
+		if window, ok := screen.focusPath[len(screen.focusPath)-2].(*Window); ok && window.menuBar != nil {
+			bars = append(bars, window.menuBar)
+		}
+	}
+	for _, child := range screen.Children() {
+		if bar, ok := child.(*MenuBar); ok {
+			bars = append(bars, bar)
+		}
+	}
+	for _, bar := range bars {
+		if bar.enabled && bar.VisibleRecursive() && bar.openMnemonic(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -92,43 +92,38 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 	}
 	axis1 := int(b.orientation)
 	axis2 := (int(b.orientation) + 1) % 2
-	position := b.margin
+	padding := widget.Padding()
+	paddingStart := [2]int{padding.Left, padding.Top}
+	paddingSize := [2]int{padding.Horizontal(), padding.Vertical()}
+	position := b.margin + paddingStart[axis1]
 
 	var yOffset int
 
 	if _, ok := widget.(*nanogui.Window); ok {
 		if b.orientation == nanogui.Vertical {
-			position += widget.Theme().WindowHeaderHeight - b.margin/2
+			position += nanogui.HeaderHeight(widget) - b.margin/2
 		} else {
-			yOffset = widget.Theme().WindowHeaderHeight
+			yOffset = nanogui.HeaderHeight(widget)
 		}
 	}
+	crossStart := paddingStart[axis2] + yOffset
+	crossSize := containerSize[axis2] - yOffset - paddingSize[axis2]
 	childCount := 0
 	fixedChildren := make([]bool, widget.ChildCount())
 	preferredLength := make([][2]int, widget.ChildCount())
-	remainedLength := containerSize[axis1] - position - b.margin + b.spacing
+	remainedLength := containerSize[axis1] - position - b.margin - (paddingSize[axis1] - paddingStart[axis1]) + b.spacing
 	for i, child := range widget.Children() {
 		if child.Visible() && !child.IsPositionAbsolute() {
 			childCount++
 			if _, isScroll := child.(*nanogui.VScrollPanel); !isScroll {
 				fW, fH := child.FixedSize()
 				fs := [2]int{fW, fH}
-				preferredLength[i] = fs
-				if fs[axis1] > 0 {
-					remainedLength -= fs[axis1]
-					fixedChildren[i] = true
-					childCount--
-				} else if child.Clamp()[axis1] {
-					pW, pH := child.PreferredSize(child, ctx)
-					ps := [2]int{pW, pH}
+				ps := nanogui.OuterSize(child, ctx)
+				preferredLength[i] = ps
+				if fs[axis1] > 0 || child.Clamp()[axis1] || nanogui.AxisSizePolicy(child, axis1) == nanogui.SizeFixed {
 					remainedLength -= ps[axis1]
-					preferredLength[i] = ps
 					fixedChildren[i] = true
 					childCount--
-				} else {
-					pW, pH := child.PreferredSize(child, ctx)
-					ps := [2]int{pW, pH}
-					preferredLength[i] = ps
 				}
 			}
 			remainedLength -= b.spacing
@@ -147,8 +142,8 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 			continue
 		}
 		var pos [2]int
-		pos[1] = yOffset
 		pos[axis1] = position
+		pos[axis2] = crossStart
 		var targetSize [2]int
 		if fixedChildren[i] && preferredLength[i][axis1] > 0 {
 			targetSize[axis1] = preferredLength[i][axis1]
@@ -157,19 +152,19 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 		}
 		targetSize[axis2] = preferredLength[i][axis2]
 
-		switch b.alignment {
+		switch nanogui.ChildAlignment(child, axis2, b.alignment) {
 		case nanogui.Minimum:
 			pos[axis2] += b.margin
 		case nanogui.Middle:
-			pos[axis2] += (containerSize[axis2] - yOffset - targetSize[axis2]) / 2
+			pos[axis2] += (crossSize - targetSize[axis2]) / 2
 		case nanogui.Maximum:
-			pos[axis2] += containerSize[axis2] - yOffset - targetSize[axis2] - b.margin*2
+			pos[axis2] += crossSize - targetSize[axis2] - b.margin*2
 		case nanogui.Fill:
 			pos[axis2] += b.margin
-			targetSize[axis2] = containerSize[axis2] - yOffset - b.margin*2
+			targetSize[axis2] = crossSize - b.margin*2
 		}
-		child.SetPosition(pos[0], pos[1])
-		child.SetSize(targetSize[0], targetSize[1])
+		targetSize[0], targetSize[1] = nanogui.FitOuterSize(child, ctx, targetSize[0], targetSize[1])
+		nanogui.PlaceWidget(child, pos[0], pos[1], targetSize[0], targetSize[1])
 		child.OnPerformLayout(child, ctx)
 		position += targetSize[axis1] + b.spacing
 	}
@@ -199,8 +194,7 @@ func (b *ExpandBoxLayout) PreferredSize(widget nanogui.Widget, ctx *nanovgo.Cont
 			continue
 		}
 		childCount++
-		w, h := child.PreferredSize(child, ctx)
-		size := []int{w, h}
+		size := nanogui.OuterSize(child, ctx)
 		minimumContainerSize[axis1] += size[axis1]
 		if size[axis2] > minimumContainerSize[axis2] {
 			minimumContainerSize[axis2] = size[axis2]
@@ -210,6 +204,9 @@ func (b *ExpandBoxLayout) PreferredSize(widget nanogui.Widget, ctx *nanovgo.Cont
 		minimumContainerSize[axis1] += (childCount-1) * b.spacing
 	}
 	minimumContainerSize[axis2] += b.margin * 2
+	padding := widget.Padding()
+	minimumContainerSize[0] += padding.Horizontal()
+	minimumContainerSize[1] += padding.Vertical()
 	for i := 0; i < 2; i++ {
 		if minimumContainerSize[i] > containerSize[i] {
 			containerSize[i] = minimumContainerSize[i]
@@ -324,11 +321,11 @@ func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.C
 
 	nCols := len(g.widths)
 
-	xOffset := g.margin
-	yOffset := g.margin
-	window, ok := widget.(*nanogui.Window)
-	if ok && window.Title() != "" {
-		yOffset += widget.Theme().WindowHeaderHeight - g.margin/2
+	padding := widget.Padding()
+	xOffset := g.margin + padding.Left
+	yOffset := g.margin + padding.Top
+	if _, ok := widget.(*nanogui.Window); ok {
+		yOffset += nanogui.HeaderHeight(widget) - g.margin/2
 	}
 
 	row := 0
@@ -336,15 +333,15 @@ func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.C
 		column := i % nCols
 		width := widths[column]
 		height := heights[row]
-		pw, ph := child.PreferredSize(child, ctx)
-		childXOffset, childWidth := alignment(g.alignments[0], width, pw)
-		childYOffset, childHeight := alignment(g.alignments[1], height, ph)
-		child.SetPosition(xOffset+childXOffset, yOffset+childYOffset)
-		child.SetSize(childWidth, childHeight)
+		ps := nanogui.OuterSize(child, ctx)
+		childXOffset, childWidth := alignment(nanogui.ChildAlignment(child, 0, g.alignments[0]), width, ps[0])
+		childYOffset, childHeight := alignment(nanogui.ChildAlignment(child, 1, g.alignments[1]), height, ps[1])
+		childWidth, childHeight = nanogui.FitOuterSize(child, ctx, childWidth, childHeight)
+		nanogui.PlaceWidget(child, xOffset+childXOffset, yOffset+childYOffset, childWidth, childHeight)
 
 		if column+1 == nCols {
 			yOffset += g.spacing[1] + height
-			xOffset = g.margin
+			xOffset = g.margin + padding.Left
 			row++
 		} else {
 			xOffset += g.spacing[0] + width
@@ -385,7 +382,8 @@ func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx *nanovgo.Conte
 	}
 
 	widths = make([]int, nCols)
-	totalWidth = 2*g.margin + (nCols-1)*g.spacing[0]
+	padding := widget.Padding()
+	totalWidth = 2*g.margin + padding.Horizontal() + (nCols-1)*g.spacing[0]
 	var totalStretch float32
 	for i, columnWidth := range g.widths {
 		totalWidth += columnWidth
@@ -402,10 +400,9 @@ func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx *nanovgo.Conte
 	for i, columnWidth := range g.widths {
 		widths[i] = columnWidth + int(float32(remainedWidth)*stretches[i]/totalStretch)
 	}
-	totalHeight = 2*g.margin + (nRows-1)*g.spacing[1]
-	window, ok := widget.(*nanogui.Window)
-	if ok && window.Title() != "" {
-		totalHeight += widget.Theme().WindowHeaderHeight - g.margin/2
+	totalHeight = 2*g.margin + padding.Vertical() + (nRows-1)*g.spacing[1]
+	if _, ok := widget.(*nanogui.Window); ok {
+		totalHeight += nanogui.HeaderHeight(widget) - g.margin/2
 	}
 	maxRowHeight := 0
 	heights = make([]int, nRows)
@@ -415,7 +412,7 @@ func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx *nanovgo.Conte
 		if fWidget, ok := child.(FlexibleWidget); ok {
 			fWidget.SetColumnWidth(widths[column])
 		}
-		_, h := child.PreferredSize(child, ctx)
+		h := nanogui.OuterSize(child, ctx)[1]
 		if h > maxRowHeight {
 			maxRowHeight = h
 		}
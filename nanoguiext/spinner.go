@@ -60,6 +60,7 @@ func (s *Spinner) SetActive(flag bool) {
 	if flag == s.Active() {
 		return
 	}
+	// FrameTime() is stale when called from an input event after the loop has been idle
 	s.filter.startTime = nanogui.GetTime()
 	if flag {
 		s.filter.state = SpinnerFadeIn
@@ -137,7 +138,7 @@ type SpinnerFilter struct {
 }
 
 func (sf *SpinnerFilter) isActive() bool {
-	currentTime := nanogui.GetTime() - sf.startTime
+	currentTime := nanogui.FrameTime() - sf.startTime
 	return sf.state == SpinnerFadeIn || (sf.state == SpinnerFadeOut && currentTime < 1.0)
 }
 
@@ -170,7 +171,9 @@ func (sf *SpinnerFilter) Draw(self nanogui.Widget, ctx *nanovgo.Context) {
 		sf.SetPosition(0, py)
 		sf.SetSize(fw, fh)
 
-		currentTime := nanogui.GetTime() - sf.startTime
+		currentTime := nanogui.FrameTime() - sf.startTime
+		// the spinner keeps moving: the main loop must not wait for events
+		nanogui.RequestAnimationFrame()
 
 		var alpha float32
 		var showSpinner bool
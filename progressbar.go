@@ -23,6 +23,7 @@ func (p *ProgressBar) Value() float32 {
 
 func (p *ProgressBar) SetValue(value float32) {
 	p.value = value
+	p.RequestRedraw()
 }
 
 func (p *ProgressBar) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
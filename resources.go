@@ -0,0 +1,191 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+	"image"
+)
+
+// ResourceManager holds the fonts, images and theme shared by all the screens
+//
+// Every Screen has its own nanovgo context; the manager keeps the font and
+// image data loaded once and registers it into each context, so that fonts
+// and images referenced by name work on any screen. The GL contexts of the
+// screens created by NewScreen() share their objects (textures, buffers,
+// shaders), so GLShader and textures created for one screen can be used by
+// the others.
+type ResourceManager struct {
+	fonts       []fontResource
+	images      []imageResource
+	handles     map[*nanovgo.Context]map[string]int
+	theme       *Theme
+	shareWindow *glfw.Window
+}
+
+type fontResource struct {
+	name string
+	data []byte
+}
+
+type imageResource struct {
+	name  string
+	flags nanovgo.ImageFlags
+	data  []byte
+	img   image.Image
+}
+
+var resources = &ResourceManager{
+	handles: make(map[*nanovgo.Context]map[string]int),
+}
+
+// Resources() returns the resource manager shared by all the screens
+func Resources() *ResourceManager {
+	return resources
+}
+
+// Theme() returns the theme new screens use; the standard theme unless SetTheme() was called
+func (r *ResourceManager) Theme() *Theme {
+	if r.theme == nil {
+		r.theme = standardTheme()
+	}
+	return r.theme
+}
+
+// SetTheme() sets the theme new screens use
+func (r *ResourceManager) SetTheme(theme *Theme) {
+	r.theme = theme
+}
+
+// AddFont() registers a font (TTF data) under a name in all the present and future screens
+func (r *ResourceManager) AddFont(name string, data []byte) {
+	for i, font := range r.fonts {
+		if font.name == name {
+			r.fonts = append(r.fonts[:i], r.fonts[i+1:]...)
+			break
+		}
+	}
+	r.fonts = append(r.fonts, fontResource{name: name, data: data})
+	for ctx := range r.handles {
+		ctx.CreateFontFromMemory(name, data, 0)
+	}
+}
+
+// AddImage() registers an encoded image (PNG, JPEG...) under a name in all the present and future screens
+func (r *ResourceManager) AddImage(name string, data []byte, flags nanovgo.ImageFlags) {
+	r.addImage(imageResource{name: name, flags: flags, data: data})
+}
+
+// AddGoImage() registers a decoded image under a name in all the present and future screens
+func (r *ResourceManager) AddGoImage(name string, img image.Image, flags nanovgo.ImageFlags) {
+	r.addImage(imageResource{name: name, flags: flags, img: img})
+}
+
+func (r *ResourceManager) addImage(resource imageResource) {
+	r.RemoveImage(resource.name)
+	r.images = append(r.images, resource)
+	r.forEachContext(func(ctx *nanovgo.Context, handles map[string]int) {
+		handles[resource.name] = resource.create(ctx)
+	})
+}
+
+// RemoveImage() deletes an image from all the screens
+func (r *ResourceManager) RemoveImage(name string) {
+	for i, resource := range r.images {
+		if resource.name == name {
+			r.images = append(r.images[:i], r.images[i+1:]...)
+			break
+		}
+	}
+	r.forEachContext(func(ctx *nanovgo.Context, handles map[string]int) {
+		if handle, ok := handles[name]; ok {
+			ctx.DeleteImage(handle)
+			delete(handles, name)
+		}
+	})
+}
+
+// forEachContext calls f for every nanovgo context, with the GL context of its screen made current;
+// the current GL context is restored afterwards
+func (r *ResourceManager) forEachContext(f func(ctx *nanovgo.Context, handles map[string]int)) {
+	if len(r.handles) == 0 {
+		return
+	}
+	previous := glfw.GetCurrentContext()
+	for ctx, handles := range r.handles {
+		for window, screen := range nanoguiScreens {
+			if screen.context == ctx {
+				window.MakeContextCurrent()
+				break
+			}
+		}
+		f(ctx, handles)
+	}
+	if previous != nil {
+		previous.MakeContextCurrent()
+	} else {
+		glfw.DetachCurrentContext()
+	}
+}
+
+// Image() returns the handle of a registered image in the nanovgo context of a screen (0 if unknown)
+func (r *ResourceManager) Image(ctx *nanovgo.Context, name string) int {
+	return r.handles[ctx][name]
+}
+
+// ImageNames() returns the names of the registered images
+func (r *ResourceManager) ImageNames() []string {
+	names := make([]string, len(r.images))
+	for i, resource := range r.images {
+		names[i] = resource.name
+	}
+	return names
+}
+
+func (i imageResource) create(ctx *nanovgo.Context) int {
+	if i.img != nil {
+		return ctx.CreateImageFromGoImage(i.flags, i.img)
+	}
+	return ctx.CreateImageFromMemory(i.flags, i.data)
+}
+
+// addContext registers the fonts and images into a new nanovgo context
+func (r *ResourceManager) addContext(ctx *nanovgo.Context) {
+	if _, ok := r.handles[ctx]; ok {
+		return
+	}
+	if len(r.fonts) == 0 {
+		r.fonts = []fontResource{
+			{name: "sans", data: MustAsset("fonts/Roboto-Regular.ttf")},
+			{name: "sans-bold", data: MustAsset("fonts/Roboto-Bold.ttf")},
+			{name: "icons", data: MustAsset("fonts/entypo.ttf")},
+		}
+	}
+	for _, font := range r.fonts {
+		ctx.CreateFontFromMemory(font.name, font.data, 0)
+	}
+	handles := make(map[string]int, len(r.images))
+	for _, resource := range r.images {
+		handles[resource.name] = resource.create(ctx)
+	}
+	r.handles[ctx] = handles
+}
+
+// removeContext forgets a nanovgo context before it is deleted
+func (r *ResourceManager) removeContext(ctx *nanovgo.Context) {
+	delete(r.handles, ctx)
+}
+
+// shareContext returns the GLFW window new windows share their GL objects with (nil for the first one)
+func (r *ResourceManager) shareContext() *glfw.Window {
+	if r.shareWindow != nil {
+		if _, ok := nanoguiScreens[r.shareWindow]; ok {
+			return r.shareWindow
+		}
+	}
+	r.shareWindow = nil
+	for window := range nanoguiScreens {
+		r.shareWindow = window
+		break
+	}
+	return r.shareWindow
+}
@@ -14,7 +14,7 @@ type Screen struct {
 	WidgetImplement
 	window                 *glfw.Window
 	context                *nanovgo.Context
-	cursors                [3]int
+	cursors                [CursorCount]*glfw.Cursor
 	cursor                 Cursor
 	focusPath              []Widget
 	fbW, fbH               int
@@ -24,7 +24,10 @@ type Screen struct {
 	mousePosX, mousePosY   int
 	dragActive             bool
 	dragWidget             Widget
+	contextMenuPress       bool
 	lastInteraction        float32
+	needsRedraw            bool
+	redrawAt               float32
 	backgroundColor        nanovgo.Color
 	caption                string
 	shutdownGLFWOnDestruct bool
@@ -32,6 +35,9 @@ type Screen struct {
 	drawContentsCallback func()
 	dropEventCallback    func([]string) bool
 	resizeEventCallback  func(x, y int) bool
+	closeRequestCallback func() bool
+	disposeCallback      func()
+	childScreens         []*Screen
 }
 
 func NewScreen(width, height int, caption string, resizable, fullScreen bool) *Screen {
@@ -53,9 +59,9 @@ func NewScreen(width, height int, caption string, resizable, fullScreen bool) *S
 	if fullScreen {
 		monitor := glfw.GetPrimaryMonitor()
 		mode := monitor.GetVideoMode()
-		screen.window, err = glfw.CreateWindow(mode.Width, mode.Height, caption, monitor, nil)
+		screen.window, err = glfw.CreateWindow(mode.Width, mode.Height, caption, monitor, resources.shareContext())
 	} else {
-		screen.window, err = glfw.CreateWindow(width, height, caption, nil, nil)
+		screen.window, err = glfw.CreateWindow(width, height, caption, nil, resources.shareContext())
 	}
 	if err != nil {
 		panic(err)
@@ -133,21 +139,84 @@ func NewScreen(width, height int, caption string, resizable, fullScreen bool) *S
 		}
 	})
 
+	screen.window.SetCloseCallback(func(w *glfw.Window) {
+		if screen, ok := nanoguiScreens[w]; ok {
+			screen.closeCallbackEvent()
+		}
+	})
+
 	screen.Initialize(screen.window, true)
 	InitWidget(screen, nil)
 	return screen
 }
 
-func finalizeScreen(s *Screen) {
+// Dispose() releases the nanovgo context and, if ShutdownGLFWOnDestruct() is set, destroys the GLFW window
+//
+// The screen must not be used afterwards; MainLoop() stops once every screen
+// is disposed or hidden. It must be called on the UI thread.
+func (s *Screen) Dispose() {
+	if s.window == nil && s.context == nil {
+		return
+	}
+	for _, child := range s.childScreens {
+		child.Dispose()
+	}
+	s.childScreens = nil
+	if s.disposeCallback != nil {
+		s.disposeCallback()
+	}
 	delete(nanoguiScreens, s.window)
 	if s.context != nil {
+		if s.window != nil {
+			s.window.MakeContextCurrent()
+		}
+		resources.removeContext(s.context)
 		s.context.Delete()
 		s.context = nil
 	}
+	for i, cursor := range s.cursors {
+		if cursor != nil {
+			cursor.Destroy()
+			s.cursors[i] = nil
+		}
+	}
 	if s.window != nil && s.shutdownGLFWOnDestruct {
 		s.window.Destroy()
-		s.window = nil
 	}
+	s.window = nil
+	s.visible = false
+}
+
+// Disposed() returns whether Dispose() has been called
+func (s *Screen) Disposed() bool {
+	return s.window == nil
+}
+
+// SetDisposeCallback() sets the callback invoked by Dispose() before the GL resources are released
+func (s *Screen) SetDisposeCallback(callback func()) {
+	s.disposeCallback = callback
+}
+
+// SetCloseRequestCallback() sets the callback invoked when the user tries to close the window; returning false keeps it open
+func (s *Screen) SetCloseRequestCallback(callback func() bool) {
+	s.closeRequestCallback = callback
+}
+
+// RequestClose() closes the window as the close button would, asking the close request callback first
+func (s *Screen) RequestClose() bool {
+	if s.closeRequestCallback != nil && !s.closeRequestCallback() {
+		return false
+	}
+	s.window.SetShouldClose(true)
+	glfw.PostEmptyEvent()
+	return true
+}
+
+func (s *Screen) closeCallbackEvent() {
+	if s.closeRequestCallback != nil && !s.closeRequestCallback() {
+		s.window.SetShouldClose(false)
+	}
+	s.needsRedraw = true
 }
 
 func (s *Screen) Initialize(window *glfw.Window, shutdownGLFWOnDestruct bool) {
@@ -161,15 +230,16 @@ func (s *Screen) Initialize(window *glfw.Window, shutdownGLFWOnDestruct bool) {
 		panic(err)
 	}
 	s.visible = true //window.GetAttrib(glfw.Visible)
-	s.theme = NewStandardTheme(s.context)
+	resources.addContext(s.context)
+	s.theme = resources.Theme()
 	s.mousePosX = 0
 	s.mousePosY = 0
 	s.mouseState = 0
 	s.modifiers = 0
 	s.dragActive = false
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 	nanoguiScreens[window] = s
-	runtime.SetFinalizer(s, finalizeScreen)
 }
 
 // Caption() gets the window title bar caption
@@ -216,6 +286,16 @@ func (s *Screen) SetSize(w, h int) {
 
 // DrawAll() draws the Screen contents
 func (s *Screen) DrawAll() {
+	// MainLoop only draws the screens that changed
+	if mainloopActive && !s.needsRedraw && !s.layoutDirty && !animationFrame && (s.redrawAt == 0 || s.redrawAt > FrameTime()) {
+		return
+	}
+	if s.layoutDirty {
+		s.updateLayout()
+	}
+	if s.redrawAt != 0 && s.redrawAt <= FrameTime() {
+		s.redrawAt = 0
+	}
 	gl.ClearColor(s.backgroundColor.R, s.backgroundColor.G, s.backgroundColor.B, 1.0)
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
 
@@ -224,6 +304,55 @@ func (s *Screen) DrawAll() {
 	}
 	s.drawWidgets()
 	s.window.SwapBuffers()
+	// changes made while drawing are already on screen
+	s.needsRedraw = false
+}
+
+// InvalidateLayout() makes the screen lay out its invalidated top level widgets at the next frame
+func (s *Screen) InvalidateLayout() {
+	s.layoutDirty = true
+	s.needsRedraw = true
+}
+
+// updateLayout lays out again the top level widgets (windows, popups) containing invalidated widgets
+func (s *Screen) updateLayout() {
+	if s.layout != nil {
+		// the layout of the screen places the top level widgets: everything is affected
+		s.PerformLayout()
+		return
+	}
+	s.layoutDirty = false
+	children := append([]Widget{}, s.children...)
+	for _, child := range children {
+		if !child.LayoutDirty() {
+			continue
+		}
+		child.SetSize(LayoutSize(child, s.context))
+		child.OnPerformLayout(child, s.context)
+		clearLayoutDirty(child)
+	}
+}
+
+// NeedsRedraw() returns whether the screen will be drawn at the next frame
+func (s *Screen) NeedsRedraw() bool {
+	return s.needsRedraw
+}
+
+// RequestRedraw() marks the screen as needing to be drawn at the next frame
+func (s *Screen) RequestRedraw() {
+	s.needsRedraw = true
+}
+
+// RequestRedrawAt() asks for the screen to be drawn at the given time (see GetTime())
+func (s *Screen) RequestRedrawAt(t float32) {
+	if s.redrawAt == 0 || t < s.redrawAt {
+		s.redrawAt = t
+	}
+}
+
+// RedrawTime() returns the time a frame was requested at with RequestRedrawAt() (0 if none)
+func (s *Screen) RedrawTime() float32 {
+	return s.redrawAt
 }
 
 // SetResizeEventCallback() sets window resize event handler
@@ -243,6 +372,9 @@ func (s *Screen) SetDropEventCallback(callback func(files []string) bool) {
 
 // KeyboardEvent() is a default key event handler
 func (s *Screen) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifiers glfw.ModifierKey) bool {
+	if action == glfw.Press && actions.dispatch(s, key, modifiers, true) {
+		return true
+	}
 	if len(s.focusPath) > 1 {
 		for i := len(s.focusPath) - 2; i >= 0; i-- {
 			path := s.focusPath[i]
@@ -251,7 +383,18 @@ func (s *Screen) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action g
 			}
 		}
 	}
-	return false
+	if action == glfw.Press && modifiers&glfw.ModAlt != 0 && openMenuBarMnemonic(s, key) {
+		return true
+	}
+	if action == glfw.Press && key == glfw.KeyMenu && len(s.focusPath) > 1 {
+		// the context menu opens below the focused widget
+		focused := s.focusPath[0]
+		x, y := focused.AbsolutePosition()
+		if openContextMenu(focused, x, y+focused.Height()) {
+			return true
+		}
+	}
+	return action == glfw.Press && actions.dispatch(s, key, modifiers, false)
 }
 
 // KeyboardCharacterEvent() is a text input event handler: codepoint is native endian UTF-32 format
@@ -358,6 +501,76 @@ func (s *Screen) DisposeWindow(window *Window) {
 	window.Parent().RemoveChild(window)
 }
 
+// AdoptWindow() moves a window, with its popups, from the screen it belongs to into this screen
+//
+// Fonts and the theme are shared by all the screens; image handles are
+// specific to a screen, so widgets showing images should use images
+// registered in Resources() and look their handle up again.
+func (s *Screen) AdoptWindow(window *Window) {
+	old := findScreen(window)
+	if old == s {
+		return
+	}
+	var popups []Widget
+	if old != nil {
+		for _, child := range old.Children() {
+			if popup, ok := child.(*Popup); ok && popupBelongsTo(popup, window) {
+				popups = append(popups, popup)
+			}
+		}
+		old.DisposeWindow(window)
+		for _, popup := range popups {
+			old.RemoveChild(popup)
+		}
+	} else if window.Parent() != nil {
+		window.Parent().RemoveChild(window)
+	}
+	s.AddChild(s, window)
+	for _, popup := range popups {
+		s.AddChild(s, popup)
+	}
+	x, y := window.Position()
+	w, h := window.Size()
+	window.SetPosition(clampI(x, 0, maxI(0, s.w-w)), clampI(y, 0, maxI(0, s.h-h)))
+	s.MoveWindowToFront(window)
+}
+
+// DetachWindow() moves a window into a new native window, placed where the window was on this screen
+//
+// The new screen is disposed together with this one.
+func (s *Screen) DetachWindow(window *Window) *Screen {
+	w, h := window.Size()
+	ax, ay := window.AbsolutePosition()
+	sx, sy := s.window.GetPos()
+	screen := NewScreen(w, h, window.Title(), true, false)
+	screen.window.SetPos(sx+ax, sy+ay)
+	screen.AdoptWindow(window)
+	window.SetPosition(0, 0)
+	screen.SetResizeEventCallback(func(w, h int) bool {
+		window.SetSize(w, h)
+		window.OnPerformLayout(window, screen.context)
+		return true
+	})
+	s.childScreens = append(s.childScreens, screen)
+	return screen
+}
+
+// popupBelongsTo returns whether a popup was opened from the window (directly or from one of its popups)
+func popupBelongsTo(popup *Popup, window *Window) bool {
+	parent := popup.ParentWindow()
+	for parent != nil {
+		if parent == IWindow(window) {
+			return true
+		}
+		next, ok := parent.(*Popup)
+		if !ok {
+			return false
+		}
+		parent = next.ParentWindow()
+	}
+	return false
+}
+
 // CenterWindow is an internal helper function
 func (s *Screen) CenterWindow(window *Window) {
 	w, h := window.Size()
@@ -401,6 +614,11 @@ func (s *Screen) MoveWindowToFront(window IWindow) {
 	}
 }
 
+// shiftPressed is an internal helper function that checks whether a shift key is currently held down
+func (s *Screen) shiftPressed() bool {
+	return s.window.GetKey(glfw.KeyLeftShift) == glfw.Press || s.window.GetKey(glfw.KeyRightShift) == glfw.Press
+}
+
 func (s *Screen) PreeditCursorPos() (int, int, int) {
 	//return s.window.GetPreeditCursorPos()
 	return -1, -1, -1
@@ -438,6 +656,9 @@ func (s *Screen) drawWidgets() {
 			posX += widget.Width() / 2
 			posY += widget.Height() + 10
 			bounds := ctx.TextBoxBounds(float32(posX), float32(posY), tooltipWidth, widget.Tooltip())
+			if elapsed < 1.0 {
+				RequestAnimationFrame()
+			}
 			ctx.SetGlobalAlpha(minF(1.0, 2*(elapsed-0.5)) * 0.8)
 			ctx.BeginPath()
 			ctx.SetFillColor(nanovgo.MONO(0, 255))
@@ -462,14 +683,15 @@ func (s *Screen) drawWidgets() {
 func (s *Screen) cursorPositionCallbackEvent(x, y float64) bool {
 	ret := false
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 
 	px := int(x) - 1
 	py := int(y) - 2
 	if !s.dragActive {
 		widget := s.FindWidget(s, int(x), int(y))
-		if widget != nil && widget.Cursor() != s.cursor {
-			//s.cursor = widget.Cursor()
-			//s.window.SetCursor()
+		if widget != nil && widget.Tooltip() != "" {
+			// the tooltip appears once the mouse rests
+			s.RequestRedrawAt(s.lastInteraction + 0.5)
 		}
 	} else {
 		ax, ay := s.dragWidget.Parent().AbsolutePosition()
@@ -478,14 +700,46 @@ func (s *Screen) cursorPositionCallbackEvent(x, y float64) bool {
 	if !ret {
 		ret = s.MouseMotionEvent(s, px, py, px-s.mousePosX, py-s.mousePosY, s.mouseState, s.modifiers)
 	}
+	// the widgets may change their cursor in the handlers above
+	if s.dragActive {
+		s.setCursor(s.dragWidget.Cursor())
+	} else if widget := s.FindWidget(s, int(x), int(y)); widget != nil {
+		s.setCursor(widget.Cursor())
+	}
 	s.mousePosX = px
 	s.mousePosY = py
 	return ret
 }
 
+var standardCursors = [CursorCount]glfw.StandardCursor{
+	glfw.ArrowCursor,
+	glfw.IBeamCursor,
+	glfw.CrosshairCursor,
+	glfw.HandCursor,
+	glfw.HResizeCursor,
+	glfw.VResizeCursor,
+}
+
+// setCursor shows a cursor on the window, creating the GLFW cursor when it is first used
+func (s *Screen) setCursor(cursor Cursor) {
+	if cursor == s.cursor || s.window == nil {
+		return
+	}
+	s.cursor = cursor
+	if cursor == Arrow {
+		s.window.SetCursor(nil)
+		return
+	}
+	if s.cursors[cursor] == nil {
+		s.cursors[cursor] = glfw.CreateStandardCursor(standardCursors[cursor])
+	}
+	s.window.SetCursor(s.cursors[cursor])
+}
+
 func (s *Screen) mouseButtonCallbackEvent(button glfw.MouseButton, action glfw.Action, modifiers glfw.ModifierKey) bool {
 	s.modifiers = modifiers
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 
 	if len(s.focusPath) > 1 {
 		window, ok := s.focusPath[len(s.focusPath)-2].(*Window)
@@ -502,15 +756,27 @@ func (s *Screen) mouseButtonCallbackEvent(button glfw.MouseButton, action glfw.A
 		s.mouseState &= ^(1 << uint(button))
 	}
 
+	if action == glfw.Press {
+		closeMenusOutside(s, s.mousePosX, s.mousePosY)
+		if button == glfw.MouseButton2 && openContextMenu(s.FindWidget(s, s.mousePosX, s.mousePosY), s.mousePosX, s.mousePosY) {
+			// the menu consumes the press and the matching release
+			s.mouseState &= ^(1 << uint(button))
+			s.contextMenuPress = true
+			return true
+		}
+	} else if button == glfw.MouseButton2 && s.contextMenuPress {
+		s.contextMenuPress = false
+		return true
+	}
+
 	dropWidget := s.FindWidget(s, s.mousePosX, s.mousePosY)
 	if s.dragActive && action == glfw.Release && dropWidget != s.dragWidget {
 		ax, ay := s.dragWidget.Parent().AbsolutePosition()
 		s.dragWidget.MouseButtonEvent(s.dragWidget, s.mousePosX-ax, s.mousePosY-ay, button, false, modifiers)
 	}
 
-	if dropWidget != nil && dropWidget.Cursor() != s.cursor {
-		//s.cursor = widget.Cursor()
-		//s.window.SetCursor()
+	if dropWidget != nil {
+		s.setCursor(dropWidget.Cursor())
 	}
 
 	if action == glfw.Press && button == glfw.MouseButton1 {
@@ -531,21 +797,25 @@ func (s *Screen) mouseButtonCallbackEvent(button glfw.MouseButton, action glfw.A
 
 func (s *Screen) keyCallbackEvent(key glfw.Key, scanCode int, action glfw.Action, modifiers glfw.ModifierKey) bool {
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 	return s.KeyboardEvent(s, key, scanCode, action, modifiers)
 }
 
 func (s *Screen) charCallbackEvent(codePoint rune) bool {
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 	return s.KeyboardCharacterEvent(s, codePoint)
 }
 
 func (s *Screen) preeditCallbackEvent(text []rune, blocks []int, focusedBlock int) {
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 	s.IMEPreeditEvent(s, text, blocks, focusedBlock)
 }
 
 func (s *Screen) imeStatusCallbackEvent() {
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 	s.IMEStatusEvent(s)
 }
 
@@ -558,6 +828,7 @@ func (s *Screen) dropCallbackEvent(fileNames []string) bool {
 
 func (s *Screen) scrollCallbackEvent(x, y float32) bool {
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
 
 	if runtime.GOOS == "windows" {
 		x *= 32
@@ -587,6 +858,13 @@ func (s *Screen) resizeCallbackEvent(width, height int) bool {
 	s.w = w
 	s.h = h
 	s.lastInteraction = GetTime()
+	s.needsRedraw = true
+	// the menu bars of the screen span its width
+	for _, child := range s.children {
+		if bar, ok := child.(*MenuBar); ok {
+			bar.SetSize(w, bar.BarHeight())
+		}
+	}
 	if s.resizeEventCallback != nil {
 		return s.resizeEventCallback(int(float32(fbW)/s.pixelRatio), int(float32(fbH)/s.pixelRatio))
 	}
@@ -597,6 +875,7 @@ func (s *Screen) resizeCallbackEvent(width, height int) bool {
 
 func (s *Screen) PerformLayout() {
 	s.OnPerformLayout(s, s.context)
+	clearLayoutDirty(s)
 }
 
 func (s *Screen) String() string {
@@ -619,6 +898,28 @@ func (s *Screen) IsClipped(cx, cy, cw, ch int) bool {
 	return false
 }
 
+// clearLayoutDirty resets the layout flag of an invalidated widget and of its invalidated descendants
+func clearLayoutDirty(widget Widget) {
+	if !widget.LayoutDirty() {
+		return
+	}
+	widget.SetLayoutDirty(false)
+	for _, child := range widget.Children() {
+		clearLayoutDirty(child)
+	}
+}
+
+// findScreen walks up the hierarchy and returns the Screen the widget belongs to (nil if it is detached)
+func findScreen(widget Widget) *Screen {
+	for widget != nil {
+		if screen, ok := widget.(*Screen); ok {
+			return screen
+		}
+		widget = widget.Parent()
+	}
+	return nil
+}
+
 func traverse(buffer *bytes.Buffer, w Widget, indent int) {
 	for i := 0; i < indent; i++ {
 		buffer.WriteString("  ")
@@ -0,0 +1,401 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+type ScrollFlags int
+
+const (
+	ScrollHorizontal ScrollFlags = 1
+	ScrollVertical   ScrollFlags = 2
+	ScrollBoth       ScrollFlags = ScrollHorizontal | ScrollVertical
+)
+
+func (f ScrollFlags) String() string {
+	switch f {
+	case ScrollHorizontal:
+		return "Horizontal"
+	case ScrollVertical:
+		return "Vertical"
+	case ScrollBoth:
+		return "Both"
+	}
+	return "None"
+}
+
+const scrollBarSize = 12
+
+// Scroll panel widget
+//
+// ScrollPanel shows its first child through a viewport that can be scrolled
+// horizontally, vertically or along both axes. The content is moved by
+// changing its position, so AbsolutePosition(), FindWidget() and IsClipped()
+// of the descendants take the scroll offset into account.
+type ScrollPanel struct {
+	WidgetImplement
+
+	flags            ScrollFlags
+	scrollX, scrollY float32
+	contentW         int
+	contentH         int
+	autoHide         bool
+	lastActivity     float32
+	dragAxis         int
+}
+
+func NewScrollPanel(parent Widget, flags ...ScrollFlags) *ScrollPanel {
+	var scrollFlags ScrollFlags
+	switch len(flags) {
+	case 0:
+		scrollFlags = ScrollBoth
+	case 1:
+		scrollFlags = flags[0]
+	default:
+		panic("NewScrollPanel can accept only one extra parameter (flags)")
+	}
+	panel := &ScrollPanel{
+		flags:    scrollFlags,
+		dragAxis: -1,
+	}
+	InitWidget(panel, parent)
+	return panel
+}
+
+// ScrollFlags() returns the axes along which the content can be scrolled
+func (p *ScrollPanel) ScrollFlags() ScrollFlags {
+	return p.flags
+}
+
+// SetScrollFlags() sets the axes along which the content can be scrolled
+func (p *ScrollPanel) SetScrollFlags(flags ScrollFlags) {
+	p.flags = flags
+	p.setScrollOffset(p.scrollX, p.scrollY)
+}
+
+// AutoHide() returns whether the scroll bars fade out when the panel is idle
+func (p *ScrollPanel) AutoHide() bool {
+	return p.autoHide
+}
+
+// SetAutoHide() sets whether the scroll bars fade out when the panel is idle
+func (p *ScrollPanel) SetAutoHide(flag bool) {
+	p.autoHide = flag
+}
+
+// Content() returns the scrolled widget (the first child), if any
+func (p *ScrollPanel) Content() Widget {
+	if len(p.children) == 0 {
+		return nil
+	}
+	return p.children[0]
+}
+
+// ScrollOffset() returns the scroll offset of the content in pixels
+func (p *ScrollPanel) ScrollOffset() (int, int) {
+	return int(p.scrollX), int(p.scrollY)
+}
+
+// SetScrollOffset() sets the scroll offset of the content in pixels
+func (p *ScrollPanel) SetScrollOffset(x, y int) {
+	p.setScrollOffset(float32(x), float32(y))
+}
+
+// Scroll() returns the scroll position along each axis in the [0, 1] range
+func (p *ScrollPanel) Scroll() (float32, float32) {
+	maxX, maxY := p.maxScroll()
+	var x, y float32
+	if maxX > 0 {
+		x = p.scrollX / maxX
+	}
+	if maxY > 0 {
+		y = p.scrollY / maxY
+	}
+	return x, y
+}
+
+// SetScroll() sets the scroll position along each axis in the [0, 1] range
+func (p *ScrollPanel) SetScroll(x, y float32) {
+	maxX, maxY := p.maxScroll()
+	p.setScrollOffset(clampF(x, 0.0, 1.0)*maxX, clampF(y, 0.0, 1.0)*maxY)
+}
+
+// ScrollTo() scrolls the minimum amount needed to make the given descendant widget visible
+func (p *ScrollPanel) ScrollTo(widget Widget) {
+	content := p.Content()
+	if content == nil {
+		return
+	}
+	var x, y int
+	w, h := widget.Size()
+	for ; widget != content; widget = widget.Parent() {
+		if widget == nil {
+			return
+		}
+		wx, wy := widget.Position()
+		x += wx
+		y += wy
+	}
+	scrollX := p.scrollX
+	scrollY := p.scrollY
+	if float32(x+w) > scrollX+float32(p.w) {
+		scrollX = float32(x + w - p.w)
+	}
+	if float32(x) < scrollX {
+		scrollX = float32(x)
+	}
+	if float32(y+h) > scrollY+float32(p.h) {
+		scrollY = float32(y + h - p.h)
+	}
+	if float32(y) < scrollY {
+		scrollY = float32(y)
+	}
+	p.setScrollOffset(scrollX, scrollY)
+	p.lastActivity = GetTime()
+}
+
+func (p *ScrollPanel) maxScroll() (float32, float32) {
+	var maxX, maxY float32
+	if p.flags&ScrollHorizontal != 0 {
+		maxX = maxF(0, float32(p.contentW-p.w))
+	}
+	if p.flags&ScrollVertical != 0 {
+		maxY = maxF(0, float32(p.contentH-p.h))
+	}
+	return maxX, maxY
+}
+
+func (p *ScrollPanel) setScrollOffset(x, y float32) {
+	maxX, maxY := p.maxScroll()
+	p.scrollX = clampF(x, 0, maxX)
+	p.scrollY = clampF(y, 0, maxY)
+	if content := p.Content(); content != nil {
+		content.SetPosition(-int(p.scrollX), -int(p.scrollY))
+	}
+}
+
+// scrollBarVisible returns whether the scroll bar along the given axis (0: horizontal, 1: vertical) is needed
+func (p *ScrollPanel) scrollBarVisible(axis int) bool {
+	maxX, maxY := p.maxScroll()
+	if axis == 0 {
+		return maxX > 0
+	}
+	return maxY > 0
+}
+
+// scrollBarRect returns the track rectangle of a scroll bar, relative to the panel
+func (p *ScrollPanel) scrollBarRect(axis int) (x, y, w, h float32) {
+	pw := float32(p.w)
+	ph := float32(p.h)
+	if axis == 0 {
+		w = pw - 8
+		if p.scrollBarVisible(1) {
+			w -= scrollBarSize
+		}
+		return 4, ph - scrollBarSize, w, 8
+	}
+	h = ph - 8
+	if p.scrollBarVisible(0) {
+		h -= scrollBarSize
+	}
+	return pw - scrollBarSize, 4, 8, h
+}
+
+// thumbRect returns the offset and length of a scroll bar thumb along its track
+func (p *ScrollPanel) thumbRect(axis int) (float32, float32) {
+	_, _, tw, th := p.scrollBarRect(axis)
+	sx, sy := p.Scroll()
+	if axis == 0 {
+		return scrollThumb(tw, float32(p.w), float32(p.contentW), sx)
+	}
+	return scrollThumb(th, float32(p.h), float32(p.contentH), sy)
+}
+
+// scrollBarAt returns the axis of the scroll bar at the given position (parent coordinates) or -1
+func (p *ScrollPanel) scrollBarAt(x, y int) int {
+	for axis := 0; axis < 2; axis++ {
+		if !p.scrollBarVisible(axis) {
+			continue
+		}
+		bx, by, bw, bh := p.scrollBarRect(axis)
+		px := float32(x - p.x)
+		py := float32(y - p.y)
+		if px >= bx && py >= by && px <= bx+bw && py <= by+bh {
+			return axis
+		}
+	}
+	return -1
+}
+
+func (p *ScrollPanel) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	content := p.Content()
+	if content == nil {
+		return
+	}
+	pW, pH := content.PreferredSize(content, ctx)
+	fW, fH := content.FixedSize()
+	w := toI(fW > 0, fW, pW)
+	h := toI(fH > 0, fH, pH)
+	if p.flags&ScrollHorizontal != 0 {
+		w = maxI(w, p.w)
+	} else {
+		w = p.w
+	}
+	if p.flags&ScrollVertical != 0 {
+		h = maxI(h, p.h)
+	} else {
+		h = p.h
+	}
+	p.contentW = w
+	p.contentH = h
+	content.SetSize(w, h)
+	p.setScrollOffset(p.scrollX, p.scrollY)
+	content.OnPerformLayout(content, ctx)
+}
+
+func (p *ScrollPanel) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	content := p.Content()
+	if content == nil {
+		return 0, 0
+	}
+	w, h := content.PreferredSize(content, ctx)
+	if p.flags&ScrollVertical != 0 {
+		w += scrollBarSize
+	}
+	if p.flags&ScrollHorizontal != 0 {
+		h += scrollBarSize
+	}
+	return w, h
+}
+
+func (p *ScrollPanel) FindWidget(self Widget, x, y int) Widget {
+	if p.scrollBarAt(x, y) != -1 {
+		return self
+	}
+	return p.WidgetImplement.FindWidget(self, x, y)
+}
+
+func (p *ScrollPanel) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 {
+		if !down && p.dragAxis != -1 {
+			p.dragAxis = -1
+			return true
+		}
+		if down {
+			if axis := p.scrollBarAt(x, y); axis != -1 {
+				p.dragAxis = axis
+				p.lastActivity = GetTime()
+				return true
+			}
+		}
+	}
+	return p.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+}
+
+func (p *ScrollPanel) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if p.dragAxis == -1 {
+		return false
+	}
+	_, _, tw, th := p.scrollBarRect(p.dragAxis)
+	_, size := p.thumbRect(p.dragAxis)
+	maxX, maxY := p.maxScroll()
+	if p.dragAxis == 0 && tw > size {
+		p.setScrollOffset(p.scrollX+float32(relX)*maxX/(tw-size), p.scrollY)
+	} else if p.dragAxis == 1 && th > size {
+		p.setScrollOffset(p.scrollX, p.scrollY+float32(relY)*maxY/(th-size))
+	}
+	p.lastActivity = GetTime()
+	return true
+}
+
+func (p *ScrollPanel) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if p.Contains(x, y) {
+		p.lastActivity = GetTime()
+	}
+	return p.WidgetImplement.MouseMotionEvent(self, x, y, relX, relY, button, modifier)
+}
+
+func (p *ScrollPanel) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	if p.WidgetImplement.ScrollEvent(self, x, y, relX, relY) {
+		return true
+	}
+	if relX == 0 {
+		if screen := findScreen(self); screen != nil && screen.shiftPressed() {
+			relX, relY = relY, 0
+		}
+	}
+	maxX, maxY := p.maxScroll()
+	if maxX == 0 && maxY == 0 {
+		return false
+	}
+	p.setScrollOffset(p.scrollX-float32(relX)*2, p.scrollY-float32(relY)*2)
+	p.lastActivity = GetTime()
+	return true
+}
+
+func (p *ScrollPanel) Draw(self Widget, ctx *nanovgo.Context) {
+	if len(p.children) == 0 {
+		return
+	}
+	x := float32(p.x)
+	y := float32(p.y)
+
+	ctx.Save()
+	ctx.IntersectScissor(x, y, float32(p.w), float32(p.h))
+	p.WidgetImplement.Draw(self, ctx)
+	ctx.Restore()
+
+	alpha := float32(1.0)
+	if p.autoHide {
+		if p.dragAxis == -1 {
+			alpha = clampF(1.5-(GetTime()-p.lastActivity)*2, 0.0, 1.0)
+		}
+		if alpha == 0 {
+			return
+		}
+		// keep drawing until the scroll bars faded out
+		RequestAnimationFrame()
+	}
+	ctx.Save()
+	ctx.SetGlobalAlpha(ctx.GlobalAlpha() * alpha)
+	for axis := 0; axis < 2; axis++ {
+		if !p.scrollBarVisible(axis) {
+			continue
+		}
+		bx, by, bw, bh := p.scrollBarRect(axis)
+		offset, size := p.thumbRect(axis)
+		drawScrollBar(ctx, axis == 0, x+bx, y+by, bw, bh, offset, size)
+	}
+	ctx.Restore()
+}
+
+func (p *ScrollPanel) String() string {
+	return p.StringHelper("ScrollPanel", fmt.Sprintf("%s,%d,%d", p.flags, int(p.scrollX), int(p.scrollY)))
+}
+
+// scrollThumb computes the offset and length of a scroll bar thumb for a track of the given length
+func scrollThumb(track, view, content, scroll float32) (float32, float32) {
+	size := minF(maxF(20.0, track*minF(1.0, view/content)), track)
+	return (track - size) * scroll, size
+}
+
+// drawScrollBar draws a scroll bar track and its thumb (offset and size are relative to the track)
+func drawScrollBar(ctx *nanovgo.Context, horizontal bool, x, y, w, h, offset, size float32) {
+	paint := nanovgo.BoxGradient(x+1, y+1, w, h, 3, 4, nanovgo.MONO(0, 32), nanovgo.MONO(0, 92))
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, h, 3)
+	ctx.SetFillPaint(paint)
+	ctx.Fill()
+
+	tx, ty, tw, th := x, y+offset, w, size
+	if horizontal {
+		tx, ty, tw, th = x+offset, y, size, h
+	}
+	barPaint := nanovgo.BoxGradient(tx-1, ty-1, tw, th, 3, 4, nanovgo.MONO(220, 100), nanovgo.MONO(128, 100))
+	ctx.BeginPath()
+	ctx.RoundedRect(tx+1, ty+1, tw-2, th-2, 2)
+	ctx.SetFillPaint(barPaint)
+	ctx.Fill()
+}
@@ -4,20 +4,54 @@ import (
 	"fmt"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/maxfish/vg4go-gl4"
+	"math"
 )
 
+// Slider widget
+//
+// The value ranges between Min() and Max() (0..1 by default) and can snap to
+// a step. In range mode the slider has two thumbs and selects a low/high pair;
+// the highlighted range then follows the thumbs.
 type Slider struct {
 	WidgetImplement
 
-	value            float32
-	highlightColor   nanovgo.Color
-	highlightedRange [2]float32
-	callback         func(float32)
-	finalCallback    func(float32)
+	value              float32
+	lowValue           float32
+	min                float32
+	max                float32
+	step               float32
+	orientation        Orientation
+	rangeMode          bool
+	activeThumb        int
+	tickCount          int
+	tickLabels         bool
+	tickFormat         string
+	relativeDrag       bool
+	fineFactor         float32
+	hasDefault         bool
+	defaultLow         float32
+	defaultValue       float32
+	showValueLabel     bool
+	valueFormat        string
+	dragging           bool
+	dragValue          float32
+	holdThumb          bool
+	lastClick          float32
+	highlightColor     nanovgo.Color
+	highlightedRange   [2]float32
+	callback           func(float32)
+	finalCallback      func(float32)
+	rangeCallback      func(float32, float32)
+	finalRangeCallback func(float32, float32)
 }
 
 func NewSlider(parent Widget) *Slider {
-	slider := &Slider{}
+	slider := &Slider{
+		max:         1.0,
+		tickFormat:  "%g",
+		fineFactor:  0.1,
+		valueFormat: "%.2f",
+	}
 	InitWidget(slider, parent)
 	return slider
 }
@@ -27,7 +61,166 @@ func (s *Slider) Value() float32 {
 }
 
 func (s *Slider) SetValue(v float32) {
-	s.value = v
+	s.value = s.snap(v)
+	if s.rangeMode && s.value < s.lowValue {
+		s.lowValue = s.value
+	}
+	s.RequestRedraw()
+}
+
+// Range() returns the low and high values of a range slider
+func (s *Slider) Range() (float32, float32) {
+	return s.lowValue, s.value
+}
+
+// SetRange() sets the low and high values of a range slider
+func (s *Slider) SetRange(low, high float32) {
+	low = s.snap(low)
+	high = s.snap(high)
+	if low > high {
+		low, high = high, low
+	}
+	s.lowValue = low
+	s.value = high
+	s.RequestRedraw()
+}
+
+// Min() returns the value at the start of the slider
+func (s *Slider) Min() float32 {
+	return s.min
+}
+
+// Max() returns the value at the end of the slider
+func (s *Slider) Max() float32 {
+	return s.max
+}
+
+// SetMinMax() sets the values at the start and at the end of the slider
+func (s *Slider) SetMinMax(min, max float32) {
+	s.min = min
+	s.max = max
+	s.SetRange(s.lowValue, s.value)
+}
+
+// Step() returns the step values snap to (0: continuous)
+func (s *Slider) Step() float32 {
+	return s.step
+}
+
+// SetStep() sets the step values snap to (0: continuous)
+func (s *Slider) SetStep(step float32) {
+	s.step = step
+	s.SetRange(s.lowValue, s.value)
+}
+
+func (s *Slider) Orientation() Orientation {
+	return s.orientation
+}
+
+// SetOrientation() sets the direction of the slider; vertical sliders have the minimum at the bottom
+func (s *Slider) SetOrientation(o Orientation) {
+	s.orientation = o
+}
+
+// RangeMode() returns whether the slider has two thumbs
+func (s *Slider) RangeMode() bool {
+	return s.rangeMode
+}
+
+// SetRangeMode() switches between a single thumb and a low/high pair of thumbs
+func (s *Slider) SetRangeMode(rangeMode bool) {
+	s.rangeMode = rangeMode
+	s.activeThumb = 1
+	if rangeMode {
+		s.lowValue = minF(s.lowValue, s.value)
+	}
+}
+
+// TickCount() returns the number of tick marks drawn along the slider
+func (s *Slider) TickCount() int {
+	return s.tickCount
+}
+
+// SetTickCount() sets the number of tick marks drawn along the slider (0: none, otherwise at least 2)
+func (s *Slider) SetTickCount(count int) {
+	if count == 1 {
+		count = 2
+	}
+	s.tickCount = count
+}
+
+// TickLabels() returns whether the tick marks are labeled with their values
+func (s *Slider) TickLabels() bool {
+	return s.tickLabels
+}
+
+// SetTickLabels() sets whether the tick marks are labeled with their values
+func (s *Slider) SetTickLabels(labels bool) {
+	s.tickLabels = labels
+}
+
+// SetTickFormat() sets the fmt format used for the tick labels ("%g" by default)
+func (s *Slider) SetTickFormat(format string) {
+	s.tickFormat = format
+}
+
+// RelativeDrag() returns whether dragging moves the thumb relative to the grab point
+func (s *Slider) RelativeDrag() bool {
+	return s.relativeDrag
+}
+
+// SetRelativeDrag() sets whether dragging moves the thumb relative to the grab point instead of jumping to the mouse
+func (s *Slider) SetRelativeDrag(relative bool) {
+	s.relativeDrag = relative
+}
+
+// FineFactor() returns the scale applied to the mouse movement while Shift is held
+func (s *Slider) FineFactor() float32 {
+	return s.fineFactor
+}
+
+// SetFineFactor() sets the scale applied to the mouse movement while Shift is held (0.1 by default)
+func (s *Slider) SetFineFactor(factor float32) {
+	s.fineFactor = factor
+}
+
+// DefaultValue() returns the value restored by a double click and whether one is set
+func (s *Slider) DefaultValue() (float32, bool) {
+	return s.defaultValue, s.hasDefault
+}
+
+// SetDefaultValue() sets the value restored by a double click
+func (s *Slider) SetDefaultValue(v float32) {
+	s.defaultLow = v
+	s.defaultValue = v
+	s.hasDefault = true
+}
+
+// SetDefaultRange() sets the low/high pair restored by a double click on a range slider
+func (s *Slider) SetDefaultRange(low, high float32) {
+	s.defaultLow = low
+	s.defaultValue = high
+	s.hasDefault = true
+}
+
+// ClearDefaultValue() disables the reset on double click
+func (s *Slider) ClearDefaultValue() {
+	s.hasDefault = false
+}
+
+// ShowValueLabel() returns whether a label with the value follows the thumb while dragging
+func (s *Slider) ShowValueLabel() bool {
+	return s.showValueLabel
+}
+
+// SetShowValueLabel() sets whether a label with the value follows the thumb while dragging
+func (s *Slider) SetShowValueLabel(show bool) {
+	s.showValueLabel = show
+}
+
+// SetValueFormat() sets the fmt format of the value label ("%.2f" by default)
+func (s *Slider) SetValueFormat(format string) {
+	s.valueFormat = format
 }
 
 func (s *Slider) HighlightColor() nanovgo.Color {
@@ -47,21 +240,136 @@ func (s *Slider) SetHighlightedRange(l, h float32) {
 	s.highlightedRange[1] = h
 }
 
+// SetCallback() sets the callback invoked while the value changes; in range mode it receives the moved thumb's value
 func (s *Slider) SetCallback(callback func(float32)) {
 	s.callback = callback
 }
 
+// SetFinalCallback() sets the callback invoked when the mouse button is pressed or released
 func (s *Slider) SetFinalCallback(callback func(float32)) {
 	s.finalCallback = callback
 }
 
+// SetRangeCallback() sets the callback invoked with the low/high pair while a range slider changes
+func (s *Slider) SetRangeCallback(callback func(low, high float32)) {
+	s.rangeCallback = callback
+}
+
+// SetFinalRangeCallback() sets the callback invoked with the low/high pair when the mouse button is pressed or released
+func (s *Slider) SetFinalRangeCallback(callback func(low, high float32)) {
+	s.finalRangeCallback = callback
+}
+
+// snap clamps a value to min..max and rounds it to the step
+func (s *Slider) snap(v float32) float32 {
+	lo, hi := minF(s.min, s.max), maxF(s.min, s.max)
+	if s.step > 0 {
+		v = s.min + float32(math.Round(float64((v-s.min)/s.step)))*s.step
+	}
+	return clampF(v, lo, hi)
+}
+
+// ratio returns the position of a value along the slider (0..1)
+func (s *Slider) ratio(v float32) float32 {
+	if s.max == s.min {
+		return 0
+	}
+	return clampF((v-s.min)/(s.max-s.min), 0.0, 1.0)
+}
+
+// valueAt returns the value under the given position (parent coordinates)
+func (s *Slider) valueAt(x, y int) float32 {
+	var ratio float32
+	if s.orientation == Vertical {
+		ratio = 1.0 - clampF(float32(y-s.y)/float32(s.h), 0.0, 1.0)
+	} else {
+		ratio = clampF(float32(x-s.x)/float32(s.w), 0.0, 1.0)
+	}
+	return s.snap(s.min + ratio*(s.max-s.min))
+}
+
+// tickAreaSize returns the space taken by the tick marks and their labels
+func (s *Slider) tickAreaSize() int {
+	if s.tickCount == 0 {
+		return 0
+	}
+	size := 6
+	if s.tickLabels {
+		size += s.FontSize() + 2
+	}
+	return size
+}
+
+// moveThumb sets the value of the active thumb
+func (s *Slider) moveThumb(v float32) {
+	if s.rangeMode && s.activeThumb == 0 {
+		s.lowValue = minF(v, s.value)
+	} else if s.rangeMode {
+		s.value = maxF(v, s.lowValue)
+	} else {
+		s.value = v
+	}
+	if s.callback != nil {
+		if s.rangeMode && s.activeThumb == 0 {
+			s.callback(s.lowValue)
+		} else {
+			s.callback(s.value)
+		}
+	}
+	if s.rangeMode && s.rangeCallback != nil {
+		s.rangeCallback(s.lowValue, s.value)
+	}
+}
+
+// thumbValue returns the value of the active thumb
+func (s *Slider) thumbValue() float32 {
+	if s.rangeMode && s.activeThumb == 0 {
+		return s.lowValue
+	}
+	return s.value
+}
+
+// onThumb returns whether the given position (parent coordinates) is over the thumb showing v
+func (s *Slider) onThumb(x, y int, v float32) bool {
+	if s.orientation == Vertical {
+		ky := float32(s.y) + (1.0-s.ratio(v))*float32(s.h)
+		return absF(float32(y)-ky) <= float32(s.w-s.tickAreaSize())*0.5
+	}
+	kx := float32(s.x) + s.ratio(v)*float32(s.w)
+	return absF(float32(x)-kx) <= float32(s.h-s.tickAreaSize())*0.5
+}
+
 func (s *Slider) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
 	if !s.enabled {
 		return false
 	}
-	s.value = clampF(float32(x-s.x)/float32(s.w), 0.0, 1.0)
-	if s.callback != nil {
-		s.callback(s.value)
+	fine := modifier&glfw.ModShift != 0
+	if s.relativeDrag || fine {
+		// accumulate the movement, so that small steps are not lost to the snapping
+		var delta float32
+		if s.orientation == Vertical {
+			delta = -float32(relY) / float32(s.h)
+		} else {
+			delta = float32(relX) / float32(s.w)
+		}
+		if fine {
+			delta *= s.fineFactor
+		}
+		s.dragValue += delta * (s.max - s.min)
+		// clamped, so that the thumb follows as soon as the mouse comes back from beyond an end
+		switch {
+		case s.rangeMode && s.activeThumb == 0:
+			s.dragValue = clampF(s.dragValue, s.min, s.value)
+		case s.rangeMode:
+			s.dragValue = clampF(s.dragValue, s.lowValue, s.max)
+		default:
+			s.dragValue = clampF(s.dragValue, s.min, s.max)
+		}
+		s.holdThumb = true
+		s.moveThumb(s.snap(s.dragValue))
+	} else {
+		s.dragValue = s.valueAt(x, y)
+		s.moveThumb(s.dragValue)
 	}
 	return true
 }
@@ -70,29 +378,110 @@ func (s *Slider) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton
 	if !s.enabled {
 		return false
 	}
-	s.value = clampF(float32(x-s.x)/float32(s.w), 0.0, 1.0)
-	if s.callback != nil {
-		s.callback(s.value)
+	v := s.valueAt(x, y)
+	if down && s.rangeMode {
+		// pick the closest thumb; when they overlap, the direction decides
+		switch {
+		case v < s.lowValue:
+			s.activeThumb = 0
+		case v > s.value:
+			s.activeThumb = 1
+		case v-s.lowValue < s.value-v:
+			s.activeThumb = 0
+		default:
+			s.activeThumb = 1
+		}
+	}
+	s.dragging = down
+	if down {
+		now := GetTime()
+		doubleClick := now-s.lastClick < 0.25
+		s.lastClick = now
+		if doubleClick && s.hasDefault {
+			s.lastClick = 0
+			if s.rangeMode {
+				s.SetRange(s.defaultLow, s.defaultValue)
+			} else {
+				s.SetValue(s.defaultValue)
+			}
+			if s.callback != nil {
+				s.callback(s.thumbValue())
+			}
+			if s.rangeMode && s.rangeCallback != nil {
+				s.rangeCallback(s.lowValue, s.value)
+			}
+			s.dragValue = s.thumbValue()
+			s.holdThumb = true
+		} else if (s.relativeDrag || modifier&glfw.ModShift != 0) && s.onThumb(x, y, s.thumbValue()) {
+			// grab the thumb where it is
+			s.dragValue = s.thumbValue()
+			s.holdThumb = true
+		} else {
+			s.dragValue = v
+			s.holdThumb = false
+			s.moveThumb(v)
+		}
+	} else if !s.holdThumb && !s.relativeDrag {
+		// the release doesn't move a thumb that was reset, grabbed or dragged relatively
+		s.moveThumb(v)
 	}
 	if s.finalCallback != nil {
-		s.finalCallback(s.value)
+		s.finalCallback(s.thumbValue())
+	}
+	if s.rangeMode && s.finalRangeCallback != nil {
+		s.finalRangeCallback(s.lowValue, s.value)
 	}
 	return true
 }
 
 func (s *Slider) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
-	return 70, 12
+	ticks := s.tickAreaSize()
+	if s.tickLabels && s.orientation == Vertical && s.tickCount > 0 {
+		ctx.SetFontSize(float32(s.FontSize()))
+		w, _ := ctx.TextBounds(0, 0, fmt.Sprintf(s.tickFormat, s.max))
+		ticks = 8 + int(w)
+	}
+	if s.orientation == Vertical {
+		return 12 + ticks, 70
+	}
+	return 70, 12 + ticks
 }
 
 func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
-	sx := float32(s.x)
-	sy := float32(s.y)
-	sw := float32(s.w)
-	sh := float32(s.h)
-	cy := sy + sh*0.5
-	kx := sx + s.value*sw
-	ky := cy + 0.5
-	kr := sh * 0.5
+	vertical := s.orientation == Vertical
+	// the slider is drawn along the "main" axis; the tick marks sit after the track on the cross axis
+	var sx, sw, cy, kr float32
+	ticks := float32(s.tickAreaSize())
+	if vertical {
+		if s.tickLabels && s.tickCount > 0 {
+			ctx.SetFontSize(float32(s.FontSize()))
+			w, _ := ctx.TextBounds(0, 0, fmt.Sprintf(s.tickFormat, s.max))
+			ticks = 8 + w
+		}
+		sx = float32(s.y)
+		sw = float32(s.h)
+		kr = maxF(float32(s.w)-ticks, 2) * 0.5
+		cy = float32(s.x) + kr
+	} else {
+		sx = float32(s.x)
+		sw = float32(s.w)
+		kr = maxF(float32(s.h)-ticks, 2) * 0.5
+		cy = float32(s.y) + kr
+	}
+	// pos maps a ratio to the main axis; the minimum of a vertical slider is at the bottom
+	pos := func(ratio float32) float32 {
+		if vertical {
+			return sx + (1.0-ratio)*sw
+		}
+		return sx + ratio*sw
+	}
+	rect := func(from, to, cross, thickness float32) {
+		if vertical {
+			ctx.RoundedRect(cross, minF(from, to), thickness, absF(to-from), 2)
+		} else {
+			ctx.RoundedRect(minF(from, to), cross, absF(to-from), thickness, 2)
+		}
+	}
 
 	var a1, a2, a3 uint8
 	if s.enabled {
@@ -104,20 +493,122 @@ func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
 		a2 = 210
 		a3 = 100
 	}
-	background := nanovgo.BoxGradient(sx, cy-3+1, sw, 6, 3, 3, nanovgo.MONO(0, a1), nanovgo.MONO(0, a2))
+	var background nanovgo.Paint
+	if vertical {
+		background = nanovgo.BoxGradient(cy-3+1, sx, 6, sw, 3, 3, nanovgo.MONO(0, a1), nanovgo.MONO(0, a2))
+	} else {
+		background = nanovgo.BoxGradient(sx, cy-3+1, sw, 6, 3, 3, nanovgo.MONO(0, a1), nanovgo.MONO(0, a2))
+	}
 
 	ctx.BeginPath()
-	ctx.RoundedRect(sx, cy-3+1, sw, 6, 2)
+	rect(sx, sx+sw, cy-3+1, 6)
 	ctx.SetFillPaint(background)
 	ctx.Fill()
 
-	if s.highlightedRange[0] != s.highlightedRange[1] {
+	highlight := s.highlightedRange
+	if s.rangeMode {
+		highlight = [2]float32{s.ratio(s.lowValue), s.ratio(s.value)}
+	}
+	if highlight[0] != highlight[1] {
 		ctx.BeginPath()
-		ctx.RoundedRect(sx+s.highlightedRange[0]*sw, cy-3+1, sw*(s.highlightedRange[1]-s.highlightedRange[0]), 6, 2)
+		rect(pos(highlight[0]), pos(highlight[1]), cy-3+1, 6)
 		ctx.SetFillColor(s.highlightColor)
 		ctx.Fill()
 	}
 
+	if s.tickCount > 0 {
+		ctx.BeginPath()
+		for i := 0; i < s.tickCount; i++ {
+			p := floorF(pos(float32(i)/float32(s.tickCount-1))) + 0.5
+			if vertical {
+				ctx.MoveTo(cy+kr+1, p)
+				ctx.LineTo(cy+kr+5, p)
+			} else {
+				ctx.MoveTo(p, cy+kr+1)
+				ctx.LineTo(p, cy+kr+5)
+			}
+		}
+		ctx.SetStrokeWidth(1.0)
+		ctx.SetStrokeColor(s.theme.BorderLight)
+		ctx.Stroke()
+
+		if s.tickLabels {
+			ctx.SetFontSize(float32(s.FontSize()))
+			ctx.SetFontFace(s.theme.FontNormal)
+			if s.enabled {
+				ctx.SetFillColor(s.theme.TextColor)
+			} else {
+				ctx.SetFillColor(s.theme.DisabledTextColor)
+			}
+			if vertical {
+				ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+			} else {
+				ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignTop)
+			}
+			for i := 0; i < s.tickCount; i++ {
+				ratio := float32(i) / float32(s.tickCount-1)
+				label := fmt.Sprintf(s.tickFormat, s.min+ratio*(s.max-s.min))
+				if vertical {
+					ctx.Text(cy+kr+8, pos(ratio), label)
+				} else {
+					ctx.Text(pos(ratio), cy+kr+7, label)
+				}
+			}
+		}
+	}
+
+	if s.rangeMode {
+		s.drawKnob(ctx, pos(s.ratio(s.lowValue)), cy, kr, vertical, a3)
+	}
+	s.drawKnob(ctx, pos(s.ratio(s.value)), cy, kr, vertical, a3)
+
+	if s.showValueLabel && s.dragging {
+		s.drawValueLabel(ctx, pos(s.ratio(s.thumbValue())), cy, kr, vertical)
+	}
+}
+
+// drawValueLabel draws a tooltip-like bubble with the value of the active thumb next to it
+func (s *Slider) drawValueLabel(ctx *nanovgo.Context, p, cy, kr float32, vertical bool) {
+	text := fmt.Sprintf(s.valueFormat, s.thumbValue())
+	ctx.SetFontFace(s.theme.FontNormal)
+	ctx.SetFontSize(float32(s.FontSize()))
+	tw, _ := ctx.TextBounds(0, 0, text)
+	th := float32(s.FontSize())
+	bw := tw + 8
+	bh := th + 4
+
+	// the bubble sits above a horizontal slider and right of a vertical one
+	var bx, by float32
+	ctx.BeginPath()
+	if vertical {
+		bx = cy + kr + 8
+		by = p - bh*0.5
+		ctx.MoveTo(bx-6, p)
+		ctx.LineTo(bx+1, p-5)
+		ctx.LineTo(bx+1, p+5)
+	} else {
+		bx = p - bw*0.5
+		by = cy - kr - bh - 6
+		ctx.MoveTo(p, by+bh+6)
+		ctx.LineTo(p-5, by+bh-1)
+		ctx.LineTo(p+5, by+bh-1)
+	}
+	ctx.RoundedRect(bx, by, bw, bh, 3)
+	ctx.SetFillColor(nanovgo.MONO(0, 204))
+	ctx.Fill()
+
+	ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+	ctx.SetFillColor(nanovgo.MONO(255, 255))
+	ctx.Text(bx+bw*0.5, by+bh*0.5, text)
+}
+
+// drawKnob draws a thumb at the given position on the main axis
+func (s *Slider) drawKnob(ctx *nanovgo.Context, p, cy, kr float32, vertical bool, alpha uint8) {
+	kx, ky := p, cy+0.5
+	if vertical {
+		kx, ky = cy+0.5, p
+	}
+
 	knobShadow := nanovgo.RadialGradient(kx, ky, kr-3, kr+3, nanovgo.MONO(0, 64), s.theme.Transparent)
 	ctx.BeginPath()
 	ctx.Rect(kx-kr-5, ky-kr-5, kr*2+10, kr*2+10+3)
@@ -126,8 +617,8 @@ func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.SetFillPaint(knobShadow)
 	ctx.Fill()
 
-	knobPaint := nanovgo.LinearGradient(sx, cy-kr, sx, cy+kr, s.theme.BorderLight, s.theme.BorderMedium)
-	knobReversePaint := nanovgo.LinearGradient(sx, cy-kr, sx, cy+kr, s.theme.BorderMedium, s.theme.BorderLight)
+	knobPaint := nanovgo.LinearGradient(kx, ky-kr, kx, ky+kr, s.theme.BorderLight, s.theme.BorderMedium)
+	knobReversePaint := nanovgo.LinearGradient(kx, ky-kr, kx, ky+kr, s.theme.BorderMedium, s.theme.BorderLight)
 
 	ctx.BeginPath()
 	ctx.Circle(kx, ky, kr)
@@ -139,11 +630,14 @@ func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.BeginPath()
 	ctx.Circle(kx, ky, kr/2)
 	ctx.SetStrokePaint(knobReversePaint)
-	ctx.SetFillColor(nanovgo.MONO(150, a3))
+	ctx.SetFillColor(nanovgo.MONO(150, alpha))
 	ctx.Stroke()
 	ctx.Fill()
 }
 
 func (s *Slider) String() string {
+	if s.rangeMode {
+		return s.StringHelper("Slider", fmt.Sprintf("%f-%f", s.lowValue, s.value))
+	}
 	return s.StringHelper("Slider", fmt.Sprintf("%f", s.value))
 }
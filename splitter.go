@@ -0,0 +1,520 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+// Splitter widget
+//
+// Splitter places its visible children (the panes) side by side,
+// horizontally or vertically, separated by dividers the user can drag to
+// resize the adjacent panes. The panes share the space according to their
+// ratios; a pane never gets smaller than the minimum pane size or its own
+// minimum size (see Widget.SetMinSize()). Double-clicking a divider collapses
+// the smaller adjacent pane, or restores it if it is collapsed.
+type Splitter struct {
+	WidgetImplement
+
+	orientation  Orientation
+	dividerSize  int
+	minPaneSize  int
+	weights      map[Widget]float32
+	collapsed    map[Widget]bool
+	lengths      []int
+	dragDivider  int
+	dragOffset   int
+	hoverDivider int
+	clickDivider int
+	lastClick    float32
+	callback     func(ratios []float32)
+}
+
+func NewSplitter(parent Widget, orientation ...Orientation) *Splitter {
+	var o Orientation
+	switch len(orientation) {
+	case 0:
+	case 1:
+		o = orientation[0]
+	default:
+		panic("NewSplitter can accept only one extra parameter (orientation)")
+	}
+	splitter := &Splitter{
+		orientation:  o,
+		dividerSize:  6,
+		minPaneSize:  20,
+		weights:      make(map[Widget]float32),
+		collapsed:    make(map[Widget]bool),
+		dragDivider:  -1,
+		hoverDivider: -1,
+		clickDivider: -1,
+	}
+	InitWidget(splitter, parent)
+	return splitter
+}
+
+// Orientation() returns whether the panes are placed side by side (Horizontal) or stacked (Vertical)
+func (s *Splitter) Orientation() Orientation {
+	return s.orientation
+}
+
+// SetOrientation() sets whether the panes are placed side by side (Horizontal) or stacked (Vertical)
+func (s *Splitter) SetOrientation(o Orientation) {
+	s.orientation = o
+	s.InvalidateLayout()
+}
+
+// DividerSize() returns the thickness of the dividers
+func (s *Splitter) DividerSize() int {
+	return s.dividerSize
+}
+
+// SetDividerSize() sets the thickness of the dividers
+func (s *Splitter) SetDividerSize(size int) {
+	s.dividerSize = size
+	s.InvalidateLayout()
+}
+
+// MinPaneSize() returns the length under which a pane can't be resized (unless it is collapsed)
+func (s *Splitter) MinPaneSize() int {
+	return s.minPaneSize
+}
+
+// SetMinPaneSize() sets the length under which a pane can't be resized (unless it is collapsed)
+func (s *Splitter) SetMinPaneSize(size int) {
+	s.minPaneSize = size
+	s.InvalidateLayout()
+}
+
+// Panes() returns the visible children, in order
+func (s *Splitter) Panes() []Widget {
+	var panes []Widget
+	for _, child := range s.children {
+		if child.Visible() && !child.IsPositionAbsolute() {
+			panes = append(panes, child)
+		}
+	}
+	return panes
+}
+
+// Ratios() returns the fraction of the space taken by each pane (0 for collapsed panes)
+func (s *Splitter) Ratios() []float32 {
+	panes := s.Panes()
+	ratios := make([]float32, len(panes))
+	var total float32
+	for _, pane := range panes {
+		if !s.collapsed[pane] {
+			total += s.weights[pane]
+		}
+	}
+	if total == 0 {
+		return ratios
+	}
+	for i, pane := range panes {
+		if !s.collapsed[pane] {
+			ratios[i] = s.weights[pane] / total
+		}
+	}
+	return ratios
+}
+
+// SetRatios() sets the fraction of the space taken by each pane, as returned by Ratios(); a pane with ratio 0 is collapsed
+func (s *Splitter) SetRatios(ratios ...float32) {
+	panes := s.Panes()
+	for i, pane := range panes {
+		if i >= len(ratios) {
+			break
+		}
+		if ratios[i] > 0 {
+			s.weights[pane] = ratios[i]
+			delete(s.collapsed, pane)
+		} else {
+			if _, ok := s.weights[pane]; !ok {
+				s.weights[pane] = 1.0 / float32(len(panes))
+			}
+			s.collapsed[pane] = true
+		}
+	}
+	s.InvalidateLayout()
+}
+
+// Collapsed() returns whether a pane is collapsed
+func (s *Splitter) Collapsed(pane Widget) bool {
+	return s.collapsed[pane]
+}
+
+// SetCollapsed() collapses a pane or restores it to its previous ratio
+func (s *Splitter) SetCollapsed(pane Widget, collapsed bool) {
+	if collapsed {
+		s.collapsed[pane] = true
+	} else {
+		delete(s.collapsed, pane)
+	}
+	s.InvalidateLayout()
+}
+
+// SetRatiosCallback() sets the callback invoked when the user resizes, collapses or restores a pane
+func (s *Splitter) SetRatiosCallback(callback func(ratios []float32)) {
+	s.callback = callback
+}
+
+// paneMinimum returns the smallest length of a pane along the axis of the splitter
+func (s *Splitter) paneMinimum(pane Widget) int {
+	minW, minH := pane.MinSize()
+	margin := pane.Margin()
+	if s.orientation == Horizontal {
+		return maxI(s.minPaneSize, minW+margin.Horizontal())
+	}
+	return maxI(s.minPaneSize, minH+margin.Vertical())
+}
+
+// contentArea returns the position and the size of the area inside the padding
+func (s *Splitter) contentArea() ([2]int, [2]int) {
+	insets := s.Padding()
+	w := toI(s.fixedW > 0, s.fixedW, s.w)
+	h := toI(s.fixedH > 0, s.fixedH, s.h)
+	return [2]int{insets.Left, insets.Top}, [2]int{w - insets.Horizontal(), h - insets.Vertical()}
+}
+
+// computeLengths returns the length of each pane sharing the available length
+func (s *Splitter) computeLengths(panes []Widget, available int, ctx *nanovgo.Context) []int {
+	axis := int(s.orientation)
+	// new panes start with a ratio matching their preferred size
+	var preferred []int
+	prefTotal := 0
+	for _, pane := range panes {
+		length := OuterSize(pane, ctx)[axis]
+		preferred = append(preferred, length)
+		prefTotal += length
+	}
+	for i, pane := range panes {
+		if _, ok := s.weights[pane]; !ok {
+			if prefTotal > 0 {
+				s.weights[pane] = float32(preferred[i]) / float32(prefTotal)
+			} else {
+				s.weights[pane] = 1.0 / float32(len(panes))
+			}
+		}
+	}
+
+	var total float32
+	for _, pane := range panes {
+		if !s.collapsed[pane] {
+			total += s.weights[pane]
+		}
+	}
+	lengths := make([]int, len(panes))
+	last := -1
+	rest := available
+	for i, pane := range panes {
+		if s.collapsed[pane] || total == 0 {
+			continue
+		}
+		lengths[i] = int(float32(available) * s.weights[pane] / total)
+		rest -= lengths[i]
+		last = i
+	}
+	if last >= 0 {
+		lengths[last] += rest
+	}
+
+	// grow the panes under their minimum at the expense of the others, starting from the last
+	for i, pane := range panes {
+		if s.collapsed[pane] {
+			continue
+		}
+		need := s.paneMinimum(pane) - lengths[i]
+		for j := len(panes) - 1; j >= 0 && need > 0; j-- {
+			if j == i || s.collapsed[panes[j]] {
+				continue
+			}
+			take := minI(need, lengths[j]-s.paneMinimum(panes[j]))
+			if take > 0 {
+				lengths[j] -= take
+				lengths[i] += take
+				need -= take
+			}
+		}
+	}
+	return lengths
+}
+
+// dividerStart returns the position of a divider along the axis, relative to the splitter
+func (s *Splitter) dividerStart(index int) int {
+	origin, _ := s.contentArea()
+	position := origin[s.orientation]
+	for i := 0; i <= index && i < len(s.lengths); i++ {
+		position += s.lengths[i]
+	}
+	return position + index*s.dividerSize
+}
+
+// dividerAt returns the index of the divider at the given position (parent coordinates) or -1
+func (s *Splitter) dividerAt(x, y int) int {
+	if !s.Contains(x, y) {
+		return -1
+	}
+	position := toI(s.orientation == Horizontal, x-s.x, y-s.y)
+	for i := 0; i+1 < len(s.lengths); i++ {
+		start := s.dividerStart(i)
+		if position >= start-2 && position < start+s.dividerSize+2 {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveDivider moves a divider to the given position along the axis, resizing the two adjacent panes
+func (s *Splitter) moveDivider(index, position int) {
+	panes := s.Panes()
+	if index+1 >= len(panes) || len(s.lengths) != len(panes) {
+		return
+	}
+	start := s.dividerStart(index) - s.lengths[index]
+	total := s.lengths[index] + s.lengths[index+1]
+	first := minI(position-start, total-s.paneMinimum(panes[index+1]))
+	first = clampI(maxI(first, s.paneMinimum(panes[index])), 0, total)
+	if first == s.lengths[index] && !s.collapsed[panes[index]] && !s.collapsed[panes[index+1]] {
+		return
+	}
+	// dragging a divider restores the collapsed panes next to it
+	delete(s.collapsed, panes[index])
+	delete(s.collapsed, panes[index+1])
+	s.lengths[index] = first
+	s.lengths[index+1] = total - first
+	s.updateWeights(panes)
+	s.InvalidateLayout()
+	if s.callback != nil {
+		s.callback(s.Ratios())
+	}
+}
+
+// toggleCollapse collapses the smaller pane next to a divider, or restores a collapsed one
+func (s *Splitter) toggleCollapse(index int) {
+	panes := s.Panes()
+	if index+1 >= len(panes) || len(s.lengths) != len(panes) {
+		return
+	}
+	before, after := panes[index], panes[index+1]
+	switch {
+	case s.collapsed[before]:
+		delete(s.collapsed, before)
+	case s.collapsed[after]:
+		delete(s.collapsed, after)
+	case s.lengths[index] <= s.lengths[index+1]:
+		s.collapsed[before] = true
+	default:
+		s.collapsed[after] = true
+	}
+	s.InvalidateLayout()
+	if s.callback != nil {
+		s.callback(s.Ratios())
+	}
+}
+
+// updateWeights sets the ratios of the panes from their current lengths
+func (s *Splitter) updateWeights(panes []Widget) {
+	total := 0
+	for i, pane := range panes {
+		if !s.collapsed[pane] {
+			total += s.lengths[i]
+		}
+	}
+	if total == 0 {
+		return
+	}
+	for i, pane := range panes {
+		if !s.collapsed[pane] {
+			s.weights[pane] = float32(s.lengths[i]) / float32(total)
+		}
+	}
+}
+
+func (s *Splitter) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	axis1 := int(s.orientation)
+	axis2 := (axis1 + 1) % 2
+	panes := s.Panes()
+	origin, area := s.contentArea()
+	// forget the removed panes
+	for pane := range s.weights {
+		if pane.Parent() != Widget(s) {
+			delete(s.weights, pane)
+		}
+	}
+	for pane := range s.collapsed {
+		if pane.Parent() != Widget(s) {
+			delete(s.collapsed, pane)
+		}
+	}
+	available := maxI(area[axis1]-s.dividerSize*maxI(len(panes)-1, 0), 0)
+	s.lengths = s.computeLengths(panes, available, ctx)
+	position := origin[axis1]
+	for i, pane := range panes {
+		var pos, size [2]int
+		pos[axis1] = position
+		pos[axis2] = origin[axis2]
+		size[axis1] = s.lengths[i]
+		size[axis2] = area[axis2]
+		PlaceWidget(pane, pos[0], pos[1], size[0], size[1])
+		pane.OnPerformLayout(pane, ctx)
+		position += s.lengths[i] + s.dividerSize
+	}
+	for _, child := range s.children {
+		if child.Visible() && child.IsPositionAbsolute() {
+			child.OnPerformLayout(child, ctx)
+		}
+	}
+}
+
+func (s *Splitter) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	axis1 := int(s.orientation)
+	axis2 := (axis1 + 1) % 2
+	var size [2]int
+	panes := s.Panes()
+	for i, pane := range panes {
+		if i > 0 {
+			size[axis1] += s.dividerSize
+		}
+		paneSize := OuterSize(pane, ctx)
+		if !s.collapsed[pane] {
+			size[axis1] += maxI(paneSize[axis1], s.paneMinimum(pane))
+		}
+		size[axis2] = maxI(size[axis2], paneSize[axis2])
+	}
+	insets := s.Padding()
+	return size[0] + insets.Horizontal(), size[1] + insets.Vertical()
+}
+
+func (s *Splitter) FindWidget(self Widget, x, y int) Widget {
+	if s.dividerAt(x, y) != -1 {
+		return self
+	}
+	return s.WidgetImplement.FindWidget(self, x, y)
+}
+
+func (s *Splitter) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 && s.enabled {
+		if !down && s.dragDivider != -1 {
+			s.dragDivider = -1
+			s.RequestRedraw()
+			return true
+		}
+		if down {
+			if index := s.dividerAt(x, y); index != -1 {
+				now := GetTime()
+				if index == s.clickDivider && now-s.lastClick < 0.25 {
+					s.lastClick = 0
+					s.toggleCollapse(index)
+					return true
+				}
+				s.lastClick = now
+				s.clickDivider = index
+				s.dragDivider = index
+				s.dragOffset = toI(s.orientation == Horizontal, x-s.x, y-s.y) - s.dividerStart(index)
+				s.RequestRedraw()
+				return true
+			}
+		}
+	}
+	return s.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+}
+
+func (s *Splitter) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if s.dragDivider == -1 {
+		return false
+	}
+	s.moveDivider(s.dragDivider, toI(s.orientation == Horizontal, x-s.x, y-s.y)-s.dragOffset)
+	return true
+}
+
+func (s *Splitter) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	index := s.dividerAt(x, y)
+	if index != s.hoverDivider {
+		s.hoverDivider = index
+		s.RequestRedraw()
+	}
+	if index != -1 || s.dragDivider != -1 {
+		s.cursor = resizeCursor(s.orientation)
+	} else {
+		s.cursor = Arrow
+	}
+	return s.WidgetImplement.MouseMotionEvent(self, x, y, relX, relY, button, modifier)
+}
+
+func (s *Splitter) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	s.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter && s.hoverDivider != -1 {
+		s.hoverDivider = -1
+		s.RequestRedraw()
+	}
+	return false
+}
+
+func (s *Splitter) Draw(self Widget, ctx *nanovgo.Context) {
+	panes := s.Panes()
+	x := float32(s.x)
+	y := float32(s.y)
+	origin, area := s.contentArea()
+
+	for i := 0; i+1 < len(panes) && i+1 < len(s.lengths); i++ {
+		start := float32(s.dividerStart(i))
+		dx, dy, dw, dh := x+start, y+float32(origin[1]), float32(s.dividerSize), float32(area[1])
+		if s.orientation == Vertical {
+			dx, dy, dw, dh = x+float32(origin[0]), y+start, float32(area[0]), float32(s.dividerSize)
+		}
+		if i == s.hoverDivider || i == s.dragDivider {
+			ctx.BeginPath()
+			ctx.Rect(dx, dy, dw, dh)
+			ctx.SetFillColor(s.theme.BorderMedium)
+			ctx.Fill()
+		}
+		// grip
+		ctx.BeginPath()
+		cx, cy := dx+dw*0.5, dy+dh*0.5
+		for j := float32(-1); j <= 1; j++ {
+			if s.orientation == Horizontal {
+				ctx.Circle(cx, cy+j*5, 1.2)
+			} else {
+				ctx.Circle(cx+j*5, cy, 1.2)
+			}
+		}
+		ctx.SetFillColor(s.theme.BorderLight)
+		ctx.Fill()
+	}
+
+	// the collapsed panes are not drawn; the others are clipped to their area
+	ctx.Translate(x, y)
+	for _, pane := range panes {
+		if s.collapsed[pane] {
+			continue
+		}
+		px, py := pane.Position()
+		pw, ph := pane.Size()
+		if pw <= 0 || ph <= 0 || self.IsClipped(px, py, pw, ph) {
+			continue
+		}
+		ctx.Save()
+		ctx.IntersectScissor(float32(px), float32(py), float32(pw), float32(ph))
+		drawWidget(pane, ctx)
+		ctx.Restore()
+	}
+	for _, child := range s.children {
+		if child.Visible() && child.IsPositionAbsolute() {
+			drawWidget(child, ctx)
+		}
+	}
+	ctx.Translate(-x, -y)
+}
+
+func (s *Splitter) String() string {
+	return s.StringHelper("Splitter", fmt.Sprintf("%s,%d", s.orientation, len(s.Panes())))
+}
+
+// resizeCursor returns the resize cursor for dragging along the axis
+func resizeCursor(orientation Orientation) Cursor {
+	if orientation == Horizontal {
+		return HResize
+	}
+	return VResize
+}
@@ -4,9 +4,13 @@ import (
 	"github.com/maxfish/vg4go-gl4"
 )
 
+// TabButton is a tab of a TabHeader
 type TabButton struct {
 	Header *TabHeader
 	Label string
+	Icon Icon
+	Closable bool
+	visibleLabel string
 	w, h int
 }
 
@@ -23,22 +27,63 @@ func (tb *TabButton) SetSize(w, h int){
 	tb.w,tb.h = w, h
 }
 
-func (tb *TabButton) PreferredSize(ctx *nanovgo.Context) (int, int){
-	labelWidth, bounds := ctx.TextBounds(0,0, tb.Label)
-	buttonWidth := int(labelWidth) + 2 * tb.Header.Theme().TabButtonHorizontalPadding
-	buttonHeight := int(bounds[3]) - int(bounds[1]) + 2 * tb.Header.Theme().TabButtonVerticalPadding
+func (tb *TabButton) Size() (int, int) {
+	return tb.w, tb.h
+}
+
+// PreferredSize() computes the size needed to show the icon, the whole label and the close button (the font must be set up)
+func (tb *TabButton) PreferredSize(ctx *nanovgo.Context) (int, int) {
+	theme := tb.Header.Theme()
+	labelWidth, bounds := ctx.TextBounds(0, 0, tb.Label)
+	buttonWidth := int(labelWidth) + 2*theme.TabButtonHorizontalPadding + tb.iconWidth() + tb.closeWidth()
+	buttonHeight := int(bounds[3]) - int(bounds[1]) + 2*theme.TabButtonVerticalPadding
 
 	return buttonWidth, buttonHeight
 }
 
+func (tb *TabButton) iconWidth() int {
+	if tb.Icon == 0 {
+		return 0
+	}
+	return int(float32(tb.Header.FontSize())*1.2) + 4
+}
+
+func (tb *TabButton) closeWidth() int {
+	if !tb.Closable {
+		return 0
+	}
+	return tb.Header.FontSize()
+}
+
+// calculateVisibleString shortens the label with an ellipsis so that it fits the width of the button (the font must be set up)
 func (tb *TabButton) calculateVisibleString(ctx *nanovgo.Context) {
-	//TODO
+	available := float32(tb.w - 2*tb.Header.Theme().TabButtonHorizontalPadding - tb.iconWidth() - tb.closeWidth())
+	if width, _ := ctx.TextBounds(0, 0, tb.Label); width <= available {
+		tb.visibleLabel = tb.Label
+		return
+	}
+	runes := []rune(tb.Label)
+	for n := len(runes) - 1; n >= 0; n-- {
+		label := string(runes[:n]) + "..."
+		if width, _ := ctx.TextBounds(0, 0, label); width <= available || n == 0 {
+			tb.visibleLabel = label
+			return
+		}
+	}
 }
 
-func (tb *TabButton) drawAtPosition(ctx *nanovgo.Context, xPos,yPos float32, active bool) {
-	w, h := tb.Header.Size()
-	width := float32(w)
-	height := float32(h)
+// closeButtonContains returns whether the close button of the tab drawn at xPos contains the position x
+func (tb *TabButton) closeButtonContains(xPos, x int) bool {
+	if !tb.Closable {
+		return false
+	}
+	end := xPos + tb.w - tb.Header.Theme().TabButtonHorizontalPadding/2
+	return x >= end-tb.closeWidth() && x < end
+}
+
+func (tb *TabButton) drawAtPosition(ctx *nanovgo.Context, xPos, yPos float32, active, closeHover bool) {
+	width := float32(tb.w)
+	height := float32(tb.h)
 	theme := tb.Header.Theme()
 
 	ctx.Save()
@@ -72,15 +117,41 @@ func (tb *TabButton) drawAtPosition(ctx *nanovgo.Context, xPos,yPos float32, act
 		ctx.SetStrokeColor(theme.BorderDark)
 		ctx.Stroke()
 	}
-	ctx.ResetScissor()  //TODO: Check this
-	ctx.Restore()
 
+	fontSize := float32(tb.Header.FontSize())
 	textX := xPos + float32(theme.TabButtonHorizontalPadding)
-	textY := yPos + float32(theme.TabButtonVerticalPadding)
+	textY := yPos + height*0.5
 	textColor := theme.TextColor
-	ctx.BeginPath()
+	if !tb.Header.Enabled() {
+		textColor = theme.DisabledTextColor
+	}
 	ctx.SetFillColor(textColor)
-	ctx.Text(textX, textY,tb.Label )  //TODO: dots handling
+	if tb.Icon != 0 {
+		ctx.SetFontSize(fontSize * 1.2)
+		ctx.SetFontFace(theme.FontIcons)
+		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+		ctx.TextRune(textX, textY, []rune{rune(tb.Icon)})
+		textX += float32(tb.iconWidth())
+	}
+	ctx.SetFontSize(fontSize)
+	ctx.SetFontFace(theme.FontNormal)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	ctx.Text(textX, textY, tb.visibleLabel)
+	if tb.Closable {
+		closeX := xPos + width - float32(theme.TabButtonHorizontalPadding/2+tb.closeWidth())
+		if closeHover {
+			ctx.BeginPath()
+			ctx.RoundedRect(closeX, textY-fontSize*0.5, float32(tb.closeWidth()), fontSize, 3)
+			ctx.SetFillColor(theme.BorderMedium)
+			ctx.Fill()
+			ctx.SetFillColor(textColor)
+		}
+		ctx.SetFontSize(fontSize * 0.9)
+		ctx.SetFontFace(theme.FontIcons)
+		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+		ctx.TextRune(closeX+float32(tb.closeWidth())*0.5, textY, []rune{rune(IconCancel)})
+	}
+	ctx.Restore()
 }
 
 //TODO: No longer used?
@@ -1,30 +1,76 @@
 package nanogui
 
 import (
+	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/maxfish/vg4go-gl4"
 )
 
+// TabHeader is the strip of tabs shown on top of a TabWidget
+//
+// A tab is selected by clicking on it and can be moved by dragging it along
+// the strip. Closable tabs show a close button. When the tabs don't fit, the
+// strip can be scrolled with the arrow controls or the mouse wheel, and a
+// drop-down control lists all the tabs.
 type TabHeader struct {
 	WidgetImplement
 	tabButtons               []*TabButton
 	activeTab                int
 	callback                 func(index int)
+	closeCallback            func(index int)
+	reorderCallback          func(from, to int)
+	removeCallback           func(index int)
 	visibleStart, visibleEnd int
-	overflowing bool
+	overflowing              bool
+	revealActive             bool
+	overflowMenu             *Popup
+
+	hoverTab     int
+	hoverClose   bool
+	pressedTab   int
+	pressedClose bool
+	pressX       int
+	dragging     bool
 }
 
 func NewTabHeader(parent Widget) *TabHeader {
-	header := &TabHeader{}
+	header := &TabHeader{
+		hoverTab:   -1,
+		pressedTab: -1,
+	}
 
 	InitWidget(header, parent)
 	return header
 }
 
+// SetCallback() sets the callback invoked when the active tab changes
+func (t *TabHeader) SetCallback(callback func(index int)) {
+	t.callback = callback
+}
+
+// SetCloseCallback() sets the callback invoked when the close button of a tab is clicked.
+// Without a callback the tab is simply removed.
+func (t *TabHeader) SetCloseCallback(callback func(index int)) {
+	t.closeCallback = callback
+}
+
+// SetReorderCallback() sets the callback invoked when a tab is moved by dragging it
+func (t *TabHeader) SetReorderCallback(callback func(from, to int)) {
+	t.reorderCallback = callback
+}
+
+// SetRemoveCallback() sets the callback invoked after a tab has been removed
+func (t *TabHeader) SetRemoveCallback(callback func(index int)) {
+	t.removeCallback = callback
+}
+
+// SetActiveTab() activates a tab and scrolls the strip to show it
 func (t *TabHeader) SetActiveTab(index int) {
 	if index < 0 || index >= len(t.tabButtons) {
 		return
 	}
 	t.activeTab = index
+	t.revealActive = true
+	t.InvalidateLayout()
 
 	if t.callback == nil {
 		return
@@ -36,6 +82,10 @@ func (t *TabHeader) ActiveTab() int {
 	return t.activeTab
 }
 
+func (t *TabHeader) TabCount() int {
+	return len(t.tabButtons)
+}
+
 func (t *TabHeader) isVisibleTab(index int) bool {
 	return index >= t.visibleStart && index < t.visibleEnd
 }
@@ -50,13 +100,64 @@ func (t *TabHeader) AddTab(index int, label string) {
 	copy(t.tabButtons[index+1:], t.tabButtons[index:])
 	t.tabButtons[index] = tab
 
+	t.closeOverflowMenu()
 	t.SetActiveTab(index)
 }
 
+// RemoveTab() removes a tab; when the active tab is removed, the following one (or the last one) is activated
 func (t *TabHeader) RemoveTab(index int) {
+	if index < 0 || index >= len(t.tabButtons) {
+		return
+	}
 	copy(t.tabButtons[index:], t.tabButtons[index+1:])
 	t.tabButtons[len(t.tabButtons)-1] = nil
 	t.tabButtons = t.tabButtons[:len(t.tabButtons)-1]
+	t.hoverTab, t.hoverClose = -1, false
+	t.closeOverflowMenu()
+	t.revealActive = true
+	t.InvalidateLayout()
+
+	switch {
+	case len(t.tabButtons) == 0:
+		t.activeTab = 0
+	case index < t.activeTab:
+		t.activeTab--
+	case index == t.activeTab:
+		t.SetActiveTab(minI(index, len(t.tabButtons)-1))
+	}
+	// the callback sees the updated active tab
+	if t.removeCallback != nil {
+		t.removeCallback(index)
+	}
+}
+
+// MoveTab() moves the tab at index 'from' to index 'to', keeping the same tab active
+func (t *TabHeader) MoveTab(from, to int) {
+	if from < 0 || from >= len(t.tabButtons) || to < 0 || to >= len(t.tabButtons) || from == to {
+		return
+	}
+	tab := t.tabButtons[from]
+	if from < to {
+		copy(t.tabButtons[from:], t.tabButtons[from+1:to+1])
+	} else {
+		copy(t.tabButtons[to+1:], t.tabButtons[to:from])
+	}
+	t.tabButtons[to] = tab
+
+	switch {
+	case t.activeTab == from:
+		t.activeTab = to
+	case from < t.activeTab && t.activeTab <= to:
+		t.activeTab--
+	case to <= t.activeTab && t.activeTab < from:
+		t.activeTab++
+	}
+	t.closeOverflowMenu()
+	t.revealActive = true
+	t.InvalidateLayout()
+	if t.reorderCallback != nil {
+		t.reorderCallback(from, to)
+	}
 }
 
 func (t *TabHeader) tabIndex(label string) (index int, ok bool) {
@@ -75,69 +176,255 @@ func (t *TabHeader) TabLabelAt(index int) string {
 	return t.tabButtons[index].Label
 }
 
-func (t *TabHeader) visibleButtonWidth() (width int) {
-	if t.visibleStart == t.visibleEnd {
+func (t *TabHeader) SetTabLabel(index int, label string) {
+	if index < 0 || index >= len(t.tabButtons) {
+		return
+	}
+	t.tabButtons[index].Label = label
+	t.InvalidateLayout()
+}
+
+func (t *TabHeader) TabIcon(index int) Icon {
+	if index < 0 || index >= len(t.tabButtons) {
 		return 0
 	}
-	width = t.x + t.Theme().TabControlWidth
-	for _, b := range t.tabButtons {
-		width += b.w
+	return t.tabButtons[index].Icon
+}
+
+// SetTabIcon() sets the icon shown before the label of a tab (0 removes it)
+func (t *TabHeader) SetTabIcon(index int, icon Icon) {
+	if index < 0 || index >= len(t.tabButtons) {
+		return
 	}
-	 return width
+	t.tabButtons[index].Icon = icon
+	t.InvalidateLayout()
 }
 
-func (t *TabHeader) activeButtonWidth() (width int) {
-	if t.visibleStart == t.visibleEnd || t.activeTab < t.visibleStart || t.activeTab >= t.visibleEnd {
-		return 0
+func (t *TabHeader) TabClosable(index int) bool {
+	if index < 0 || index >= len(t.tabButtons) {
+		return false
+	}
+	return t.tabButtons[index].Closable
+}
+
+// SetTabClosable() sets whether a tab shows a close button
+func (t *TabHeader) SetTabClosable(index int, closable bool) {
+	if index < 0 || index >= len(t.tabButtons) {
+		return
+	}
+	t.tabButtons[index].Closable = closable
+	t.InvalidateLayout()
+}
+
+// closeTab() is invoked when the close button of a tab is clicked
+func (t *TabHeader) closeTab(index int) {
+	if t.closeCallback != nil {
+		t.closeCallback(index)
+		return
+	}
+	t.RemoveTab(index)
+}
+
+// activeButtonArea() returns the horizontal extent of the active tab, relative to the header
+func (t *TabHeader) activeButtonArea() (start, end int, ok bool) {
+	if !t.isVisibleTab(t.activeTab) {
+		return 0, 0, false
 	}
-	width = t.x + t.Theme().TabControlWidth
+	start = t.theme.TabControlWidth
+	for i := t.visibleStart; i < t.activeTab; i++ {
+		start += t.tabButtons[i].w
+	}
+	return start, start + t.tabButtons[t.activeTab].w, true
+}
+
+// tabAt() returns the visible tab at the horizontal position x (in parent coordinates) and where it starts
+func (t *TabHeader) tabAt(x int) (index, start int) {
+	pos := t.x + t.theme.TabControlWidth
+	for i := t.visibleStart; i < t.visibleEnd; i++ {
+		w := t.tabButtons[i].w
+		if x >= pos && x < pos+w {
+			return i, pos
+		}
+		pos += w
+	}
+	return -1, 0
+}
+
+// visibleWidth() returns the width of the strip available to the tabs
+func (t *TabHeader) visibleWidth() int {
+	if t.overflowing {
+		return t.w - 3*t.theme.TabControlWidth
+	}
+	return t.w - 2*t.theme.TabControlWidth
+}
+
+// updateVisibleRange() decides whether the tabs overflow and which of them are shown
+func (t *TabHeader) updateVisibleRange() {
+	total := 0
 	for _, b := range t.tabButtons {
-		width += b.w
+		total += b.w
+	}
+	t.overflowing = total > t.w-2*t.theme.TabControlWidth
+	if !t.overflowing {
+		t.visibleStart = 0
+		t.visibleEnd = len(t.tabButtons)
+		return
 	}
-	return width
+	t.visibleStart = clampI(t.visibleStart, 0, maxI(len(t.tabButtons)-1, 0))
+
+	// scroll back when there is free space after the last tab
+	width := 0
+	for i := t.visibleStart; i < len(t.tabButtons); i++ {
+		width += t.tabButtons[i].w
+	}
+	for t.visibleStart > 0 && width+t.tabButtons[t.visibleStart-1].w <= t.visibleWidth() {
+		t.visibleStart--
+		width += t.tabButtons[t.visibleStart].w
+	}
+	t.calculateVisibleEnd()
 }
 
+// ensureTabVisible() scrolls the strip so that the active tab is shown
 func (t *TabHeader) ensureTabVisible() {
-//TODO
+	if !t.overflowing || t.activeTab >= len(t.tabButtons) {
+		return
+	}
+	if t.activeTab < t.visibleStart {
+		t.visibleStart = t.activeTab
+	} else if t.activeTab >= t.visibleEnd {
+		// the active tab becomes the last visible one
+		width := 0
+		start := t.activeTab
+		for start >= 0 && width+t.tabButtons[start].w <= t.visibleWidth() {
+			width += t.tabButtons[start].w
+			start--
+		}
+		t.visibleStart = minI(start+1, t.activeTab)
+	}
+	t.calculateVisibleEnd()
 }
 
 func (t *TabHeader) calculateVisibleEnd() {
-	curPos := t.Theme().TabControlWidth
-	lastPos := t.w - t.Theme().TabControlWidth
+	curPos := 0
+	lastPos := t.visibleWidth()
 
-	for i, b :=range t.tabButtons {
-		curPos += b.w
+	for i := t.visibleStart; i < len(t.tabButtons); i++ {
+		curPos += t.tabButtons[i].w
 		if curPos > lastPos {
-			t.visibleEnd = i
+			// at least one tab is shown, even if it doesn't fit
+			t.visibleEnd = maxI(i, t.visibleStart+1)
 			return
 		}
 	}
+	t.visibleEnd = len(t.tabButtons)
+}
+
+// scrollTabs() scrolls the strip by the given number of tabs
+func (t *TabHeader) scrollTabs(delta int) bool {
+	if !t.overflowing || (delta > 0 && t.visibleEnd >= len(t.tabButtons)) {
+		return false
+	}
+	start := clampI(t.visibleStart+delta, 0, len(t.tabButtons)-1)
+	if start == t.visibleStart {
+		return false
+	}
+	t.visibleStart = start
+	t.calculateVisibleEnd()
+	t.RequestRedraw()
+	return true
+}
+
+// OverflowMenuVisible() returns whether the drop-down listing all the tabs is shown
+func (t *TabHeader) OverflowMenuVisible() bool {
+	return t.overflowMenu != nil && t.overflowMenu.Visible()
+}
+
+// toggleOverflowMenu() shows or hides the drop-down listing all the tabs
+func (t *TabHeader) toggleOverflowMenu() {
+	if t.OverflowMenuVisible() {
+		t.closeOverflowMenu()
+		return
+	}
+	if t.overflowMenu == nil {
+		window := t.FindWindow()
+		t.overflowMenu = NewPopup(window.Parent(), window)
+	}
+	panel := t.overflowMenu.panel
+	for panel.ChildCount() > 0 {
+		panel.RemoveChildByIndex(panel.ChildCount() - 1)
+	}
+	panel.SetLayout(NewGroupLayout(10))
+	for i, b := range t.tabButtons {
+		index := i
+		item := NewButton(panel, b.Label)
+		item.SetFlags(RadioButtonType)
+		item.SetIcon(b.Icon)
+		item.SetPushed(i == t.activeTab)
+		item.SetCallback(func() {
+			t.closeOverflowMenu()
+			t.SetActiveTab(index)
+		})
+	}
+	t.overflowMenu.SetVisible(true)
+	t.InvalidateLayout()
+}
+
+func (t *TabHeader) closeOverflowMenu() {
+	if t.OverflowMenuVisible() {
+		t.overflowMenu.SetVisible(false)
+		t.RequestRedraw()
+	}
+}
+
+// placeOverflowMenu() sizes the drop-down and anchors it next to the drop-down control
+func (t *TabHeader) placeOverflowMenu(ctx *nanovgo.Context) {
+	panel := t.overflowMenu.panel
+	w, h := panel.PreferredSize(panel, ctx)
+	if h > 250 {
+		w += 12
+		h = 250
+	}
+	t.overflowMenu.SetSize(w, h)
+	t.overflowMenu.OnPerformLayout(t.overflowMenu, ctx)
+
+	window := t.overflowMenu.ParentWindow()
+	ax, ay := t.AbsolutePosition()
+	wx, wy := window.AbsolutePosition()
+	t.overflowMenu.SetAnchorPosition(ax-wx+t.w+15, ay-wy+t.h/2)
 }
 
 func (t *TabHeader) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
 	t.WidgetImplement.OnPerformLayout(self, ctx)
 
-	for _, b :=range t.tabButtons {
+	ctx.SetFontFace(t.theme.FontNormal)
+	ctx.SetFontSize(float32(t.FontSize()))
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
+	for _, b := range t.tabButtons {
 		prefW, _ := b.PreferredSize(ctx)
-		prefW = clampI(t.Theme().TabMinButtonWidth, t.Theme().TabMaxButtonWidth, prefW)
-		b.w = prefW
+		prefW = clampI(prefW, t.Theme().TabMinButtonWidth, t.Theme().TabMaxButtonWidth)
+		b.SetSize(prefW, t.h)
 		b.calculateVisibleString(ctx)
 	}
 
-	t.calculateVisibleEnd()
-
-	t.overflowing = t.visibleStart !=0 || t.visibleEnd != len(t.tabButtons) -1
+	t.updateVisibleRange()
+	if t.revealActive {
+		t.revealActive = false
+		t.ensureTabVisible()
+	}
+	if t.OverflowMenuVisible() {
+		t.placeOverflowMenu(ctx)
+	}
 }
 
 func (t *TabHeader) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
-	ctx.SetFontFace("sans")
-	ctx.SetFontSize(20)
+	ctx.SetFontFace(t.theme.FontNormal)
+	ctx.SetFontSize(float32(t.FontSize()))
 	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
-	w := 2*t.theme.TabControlWidth
+	w := 2 * t.theme.TabControlWidth
 	h := 0
-	for _, b :=range t.tabButtons {
+	for _, b := range t.tabButtons {
 		prefW, prefH := b.PreferredSize(ctx)
-		prefW = clampI(t.Theme().TabMinButtonWidth, t.Theme().TabMaxButtonWidth, prefW)
+		prefW = clampI(prefW, t.Theme().TabMinButtonWidth, t.Theme().TabMaxButtonWidth)
 		w += prefW
 		h = maxI(h, prefH)
 	}
@@ -145,13 +432,141 @@ func (t *TabHeader) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int)
 	return w, h
 }
 
+func (t *TabHeader) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if !t.enabled || button != glfw.MouseButton1 {
+		return false
+	}
+	if down {
+		if t.overflowing {
+			control := t.theme.TabControlWidth
+			switch {
+			case x-t.x < control:
+				t.scrollTabs(-1)
+				return true
+			case x-t.x >= t.w-control:
+				t.toggleOverflowMenu()
+				return true
+			case x-t.x >= t.w-2*control:
+				t.scrollTabs(1)
+				return true
+			}
+		}
+		index, start := t.tabAt(x)
+		if index < 0 {
+			return false
+		}
+		if !t.focused {
+			t.RequestFocus(self)
+		}
+		t.pressedTab = index
+		t.pressX = x
+		t.dragging = false
+		t.pressedClose = t.tabButtons[index].closeButtonContains(start, x)
+		if !t.pressedClose && index != t.activeTab {
+			t.SetActiveTab(index)
+		}
+		return true
+	}
+	if t.pressedTab < 0 {
+		return false
+	}
+	if t.pressedClose && !t.dragging {
+		if index, start := t.tabAt(x); index == t.pressedTab && t.tabButtons[index].closeButtonContains(start, x) {
+			t.closeTab(index)
+		}
+	}
+	t.pressedTab = -1
+	t.pressedClose = false
+	t.dragging = false
+	t.RequestRedraw()
+	return true
+}
+
+func (t *TabHeader) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if t.pressedTab < 0 {
+		return false
+	}
+	if !t.dragging && absI(x-t.pressX) > 4 {
+		t.dragging = true
+	}
+	if !t.dragging {
+		return true
+	}
+	if index, _ := t.tabAt(x); index >= 0 && index != t.pressedTab {
+		t.MoveTab(t.pressedTab, index)
+		t.pressedTab = index
+	}
+	return true
+}
+
+func (t *TabHeader) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	if !t.overflowing {
+		return false
+	}
+	switch {
+	case relY != 0:
+		t.scrollTabs(-relY)
+	case relX != 0:
+		t.scrollTabs(-relX)
+	}
+	return true
+}
+
+func (t *TabHeader) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if key == glfw.KeyEscape && action == glfw.Press && t.OverflowMenuVisible() {
+		t.closeOverflowMenu()
+		return true
+	}
+	return false
+}
+
+func (t *TabHeader) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	hoverTab, hoverClose := -1, false
+	if t.Contains(x, y) {
+		var start int
+		if hoverTab, start = t.tabAt(x); hoverTab >= 0 {
+			hoverClose = t.tabButtons[hoverTab].closeButtonContains(start, x)
+		}
+	}
+	if hoverTab != t.hoverTab || hoverClose != t.hoverClose {
+		t.hoverTab, t.hoverClose = hoverTab, hoverClose
+		t.RequestRedraw()
+	}
+	return false
+}
+
+func (t *TabHeader) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	t.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter {
+		t.hoverTab, t.hoverClose = -1, false
+		t.RequestRedraw()
+	}
+	return false
+}
+
 func (t *TabHeader) Draw(self Widget, ctx *nanovgo.Context) {
 	t.WidgetImplement.Draw(self, ctx)
 
+	// inactive tabs first, so that the active one is drawn on top of its neighbours
+	x := t.x + t.theme.TabControlWidth
+	activeX := -1
+	for i := t.visibleStart; i < t.visibleEnd; i++ {
+		b := t.tabButtons[i]
+		if i == t.activeTab {
+			activeX = x
+		} else {
+			b.drawAtPosition(ctx, float32(x), float32(t.y), false, i == t.hoverTab && t.hoverClose)
+		}
+		x += b.w
+	}
+	if activeX >= 0 {
+		b := t.tabButtons[t.activeTab]
+		b.drawAtPosition(ctx, float32(activeX), float32(t.y), true, t.activeTab == t.hoverTab && t.hoverClose)
+	}
+
 	if t.overflowing {
 		t.drawControls(ctx)
 	}
-
 }
 
 func (t *TabHeader) drawControls(ctx *nanovgo.Context) {
@@ -160,7 +575,7 @@ func (t *TabHeader) drawControls(ctx *nanovgo.Context) {
 	//Left Button
 	ctx.BeginPath()
 	iconLeft := IconLeftBold
-	fontSize := t.theme.ButtonFontSize   //TODO: Consider to handle specific FontSize
+	fontSize := t.theme.ButtonFontSize //TODO: Consider to handle specific FontSize
 	ih := float32(fontSize) * 1.5
 	ctx.SetFontSize(ih)
 	ctx.SetFontFace(t.theme.FontIcons)
@@ -179,7 +594,7 @@ func (t *TabHeader) drawControls(ctx *nanovgo.Context) {
 	ctx.TextRune(iconPosX, iconPosY, []rune{rune(iconLeft)})
 
 	// Right Button
-	if t.visibleEnd != len(t.tabButtons)-1{
+	if t.visibleEnd != len(t.tabButtons) {
 		arrowColor = t.theme.TextColor
 	} else {
 		arrowColor = t.theme.ButtonGradientBotPushed
@@ -187,9 +602,15 @@ func (t *TabHeader) drawControls(ctx *nanovgo.Context) {
 	ctx.SetFillColor(arrowColor)
 
 	iconRight := IconRightBold
-	iconPosX = float32(t.x + t.w - t.theme.TabControlWidth) - xScale*float32(t.theme.TabControlWidth)
+	iconPosX = float32(t.x+t.w-2*t.theme.TabControlWidth) + xScale*float32(t.theme.TabControlWidth)
 	ctx.TextRune(iconPosX, iconPosY, []rune{rune(iconRight)})
 
+	// Drop-down Button
+	ctx.SetFillColor(t.theme.TextColor)
+	iconPosX = float32(t.x+t.w-t.theme.TabControlWidth) + xScale*float32(t.theme.TabControlWidth)
+	ctx.TextRune(iconPosX, iconPosY, []rune{rune(IconDownBold)})
+}
 
-
-}
\ No newline at end of file
+func (t *TabHeader) String() string {
+	return t.StringHelper("TabHeader", "")
+}
@@ -0,0 +1,1006 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// TableModel provides the cells displayed by a Table
+type TableModel interface {
+	RowCount() int
+	Value(row, column int) interface{}
+}
+
+// EditableTableModel is a TableModel whose cells can be changed by cell editors
+type EditableTableModel interface {
+	TableModel
+	SetValue(row, column int, value interface{})
+}
+
+// CellRenderer draws the value of a cell (x, y, w, h is the cell rectangle)
+type CellRenderer interface {
+	DrawCell(ctx *nanovgo.Context, table *Table, value interface{}, x, y, w, h float32)
+}
+
+// CellEditor creates the widget used to edit a cell in place
+//
+// The editor has to be created as a child of the table and must call commit
+// with the new value once the user is done; the table then removes it.
+type CellEditor interface {
+	CreateEditor(table *Table, value interface{}, commit func(value interface{})) Widget
+}
+
+// TextCellRenderer draws the value of a cell as text
+type TextCellRenderer struct {
+	Alignment TextAlignment
+}
+
+func (r *TextCellRenderer) DrawCell(ctx *nanovgo.Context, table *Table, value interface{}, x, y, w, h float32) {
+	text := fmt.Sprint(value)
+	ctx.SetFontSize(float32(table.FontSize()))
+	ctx.SetFontFace(table.Theme().FontNormal)
+	ctx.SetFillColor(table.Theme().TextColor)
+	switch r.Alignment {
+	case TextLeft:
+		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+		ctx.Text(x+4, y+h*0.5, text)
+	case TextRight:
+		ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
+		ctx.Text(x+w-4, y+h*0.5, text)
+	case TextCenter:
+		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+		ctx.Text(x+w*0.5, y+h*0.5, text)
+	}
+}
+
+// CheckCellRenderer draws a boolean value as a check mark
+type CheckCellRenderer struct {
+}
+
+func (r *CheckCellRenderer) DrawCell(ctx *nanovgo.Context, table *Table, value interface{}, x, y, w, h float32) {
+	size := minF(h-6, 16)
+	bx := x + (w-size)*0.5
+	by := y + (h-size)*0.5
+	bgPaint := nanovgo.BoxGradient(bx+1.5, by+1.5, size-2.0, size-2.0, 3, 3, nanovgo.MONO(0, 32), nanovgo.MONO(0, 180))
+	ctx.BeginPath()
+	ctx.RoundedRect(bx+1.0, by+1.0, size-2.0, size-2.0, 3)
+	ctx.SetFillPaint(bgPaint)
+	ctx.Fill()
+	if checked, ok := value.(bool); ok && checked {
+		ctx.SetFontSize(size)
+		ctx.SetFontFace(table.Theme().FontIcons)
+		ctx.SetFillColor(table.Theme().IconColor)
+		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+		ctx.TextRune(bx+size*0.5+1.0, by+size*0.5, []rune{rune(IconCheck)})
+	}
+}
+
+// TextCellEditor edits a cell with a TextBox; the committed value is a string
+type TextCellEditor struct {
+	Alignment TextAlignment
+}
+
+func (e *TextCellEditor) CreateEditor(table *Table, value interface{}, commit func(value interface{})) Widget {
+	textBox := NewTextBox(table, fmt.Sprint(value))
+	textBox.SetEditable(true)
+	textBox.SetAlignment(e.Alignment)
+	textBox.SetFontSize(table.FontSize())
+	textBox.SetCallback(func(text string) bool {
+		commit(text)
+		return true
+	})
+	return textBox
+}
+
+// CheckCellEditor edits a boolean cell with a CheckBox
+type CheckCellEditor struct {
+}
+
+func (e *CheckCellEditor) CreateEditor(table *Table, value interface{}, commit func(value interface{})) Widget {
+	checkBox := NewCheckBox(table, "")
+	checked, _ := value.(bool)
+	checkBox.SetChecked(checked)
+	checkBox.SetCallback(func(checked bool) {
+		commit(checked)
+	})
+	return checkBox
+}
+
+// ComboCellEditor edits a cell holding an item index with a ComboBox; it also renders the item name
+type ComboCellEditor struct {
+	Items []string
+}
+
+func (e *ComboCellEditor) CreateEditor(table *Table, value interface{}, commit func(value interface{})) Widget {
+	comboBox := NewComboBox(table, e.Items)
+	index, _ := value.(int)
+	comboBox.SetSelectedIndex(index)
+	comboBox.SetFontSize(table.FontSize())
+	comboBox.SetCallback(func(index int) {
+		commit(index)
+	})
+	return comboBox
+}
+
+func (e *ComboCellEditor) DrawCell(ctx *nanovgo.Context, table *Table, value interface{}, x, y, w, h float32) {
+	var text string
+	if index, ok := value.(int); ok && index >= 0 && index < len(e.Items) {
+		text = e.Items[index]
+	}
+	renderer := TextCellRenderer{Alignment: TextLeft}
+	renderer.DrawCell(ctx, table, text, x, y, w, h)
+}
+
+// TableColumn describes a column of a Table
+type TableColumn struct {
+	Title    string
+	Index    int
+	Width    int
+	MinWidth int
+	Sortable bool
+	Renderer CellRenderer
+	Editor   CellEditor
+	Less     func(a, b interface{}) bool
+}
+
+func (c *TableColumn) renderer() CellRenderer {
+	if c.Renderer != nil {
+		return c.Renderer
+	}
+	if renderer, ok := c.Editor.(CellRenderer); ok {
+		return renderer
+	}
+	return &TextCellRenderer{Alignment: TextLeft}
+}
+
+// Table widget
+//
+// Table shows the cells of a TableModel in columns with a frozen header.
+// Columns can be resized by dragging the edge of their header, reordered by
+// dragging the header and sorted by clicking it. Only the visible rows are
+// drawn. Cells of columns with an editor are edited in place with a double
+// click or the Enter key.
+type Table struct {
+	WidgetImplement
+
+	model         TableModel
+	columns       []*TableColumn
+	order         []int
+	sortColumn    *TableColumn
+	sortAscending bool
+	rowHeight     int
+	selectionMode ListSelectionMode
+	selected      map[int]bool
+	anchor        int
+	current       int
+	hover         int
+	scrollX       float32
+	scrollY       float32
+	dragAxis      int
+	resizeColumn  int
+	pressColumn   int
+	pressX        int
+	moveX         int
+	moving        bool
+	editor        Widget
+	editRow       int
+	editColumn    *TableColumn
+	lastClick     float32
+	callback      func([]int)
+	sortCallback  func(column int, ascending bool)
+}
+
+func NewTable(parent Widget, models ...TableModel) *Table {
+	var model TableModel
+	switch len(models) {
+	case 0:
+	case 1:
+		model = models[0]
+	default:
+		panic("NewTable can accept only one extra parameter (model)")
+	}
+	table := &Table{
+		model:         model,
+		sortAscending: true,
+		selectionMode: ListSelectionSingle,
+		selected:      make(map[int]bool),
+		anchor:        -1,
+		current:       -1,
+		hover:         -1,
+		dragAxis:      -1,
+		resizeColumn:  -1,
+		pressColumn:   -1,
+	}
+	InitWidget(table, parent)
+	return table
+}
+
+// Model() returns the model of the table
+func (t *Table) Model() TableModel {
+	return t.model
+}
+
+// SetModel() sets the model of the table; the selection is cleared
+func (t *Table) SetModel(model TableModel) {
+	t.endEdit()
+	t.model = model
+	t.scrollY = 0
+	t.current = -1
+	t.anchor = -1
+	t.selected = make(map[int]bool)
+	t.Refresh()
+	t.notifySelection()
+}
+
+// Refresh() sorts the rows again; call it after the data of the model changed
+func (t *Table) Refresh() {
+	count := t.RowCount()
+	t.order = make([]int, count)
+	for i := range t.order {
+		t.order[i] = i
+	}
+	for row := range t.selected {
+		if row >= count {
+			delete(t.selected, row)
+		}
+	}
+	if t.sortColumn != nil {
+		column := t.sortColumn
+		less := column.Less
+		if less == nil {
+			less = lessCellValue
+		}
+		sort.SliceStable(t.order, func(i, j int) bool {
+			a := t.model.Value(t.order[i], column.Index)
+			b := t.model.Value(t.order[j], column.Index)
+			if t.sortAscending {
+				return less(a, b)
+			}
+			return less(b, a)
+		})
+	}
+	t.current = minI(t.current, count-1)
+	t.anchor = minI(t.anchor, count-1)
+}
+
+// RowCount() returns the number of rows of the model
+func (t *Table) RowCount() int {
+	if t.model == nil {
+		return 0
+	}
+	return t.model.RowCount()
+}
+
+// AddColumn() appends a column showing the next column of the model
+func (t *Table) AddColumn(title string, width int) *TableColumn {
+	column := &TableColumn{
+		Title:    title,
+		Index:    len(t.columns),
+		Width:    width,
+		MinWidth: 20,
+		Sortable: true,
+	}
+	t.columns = append(t.columns, column)
+	return column
+}
+
+// Columns() returns the columns in display order
+func (t *Table) Columns() []*TableColumn {
+	return t.columns
+}
+
+// MoveColumn() moves a column to another display position
+func (t *Table) MoveColumn(from, to int) {
+	if from < 0 || from >= len(t.columns) || to < 0 || to >= len(t.columns) || from == to {
+		return
+	}
+	column := t.columns[from]
+	t.columns = append(t.columns[:from], t.columns[from+1:]...)
+	t.columns = append(t.columns[:to], append([]*TableColumn{column}, t.columns[to:]...)...)
+}
+
+// RowHeight() returns the height of a row (and of the header)
+func (t *Table) RowHeight() int {
+	if t.rowHeight > 0 {
+		return t.rowHeight
+	}
+	return t.FontSize() + 10
+}
+
+// SetRowHeight() sets the height of a row (0: derived from the font size)
+func (t *Table) SetRowHeight(h int) {
+	t.rowHeight = h
+}
+
+// SortColumn() returns the display index of the column used to sort the rows (-1 if unsorted) and the direction
+func (t *Table) SortColumn() (int, bool) {
+	for i, column := range t.columns {
+		if column == t.sortColumn {
+			return i, t.sortAscending
+		}
+	}
+	return -1, t.sortAscending
+}
+
+// SortBy() sorts the rows by a column (display index, -1 to restore the model order)
+func (t *Table) SortBy(column int, ascending bool) {
+	if column < 0 || column >= len(t.columns) {
+		t.sortColumn = nil
+	} else {
+		t.sortColumn = t.columns[column]
+	}
+	t.sortAscending = ascending
+	t.endEdit()
+	t.Refresh()
+	if t.sortCallback != nil {
+		t.sortCallback(column, ascending)
+	}
+}
+
+// SetSortCallback() sets the callback invoked when the sort column or direction changes
+func (t *Table) SetSortCallback(callback func(column int, ascending bool)) {
+	t.sortCallback = callback
+}
+
+// SelectionMode() returns how many rows can be selected
+func (t *Table) SelectionMode() ListSelectionMode {
+	return t.selectionMode
+}
+
+// SetSelectionMode() sets how many rows can be selected
+func (t *Table) SetSelectionMode(mode ListSelectionMode) {
+	t.selectionMode = mode
+	if mode != ListSelectionMultiple && len(t.selected) > 0 {
+		t.selected = make(map[int]bool)
+		t.notifySelection()
+	}
+}
+
+// Selection() returns the selected rows (model indices) in ascending order
+func (t *Table) Selection() []int {
+	selection := make([]int, 0, len(t.selected))
+	for row := range t.selected {
+		selection = append(selection, row)
+	}
+	sort.Ints(selection)
+	return selection
+}
+
+// SetSelection() replaces the selection (model indices)
+func (t *Table) SetSelection(rows ...int) {
+	if t.selectionMode == ListSelectionNone {
+		return
+	}
+	if t.selectionMode == ListSelectionSingle && len(rows) > 1 {
+		rows = rows[len(rows)-1:]
+	}
+	t.selected = make(map[int]bool)
+	for _, row := range rows {
+		if row >= 0 && row < t.RowCount() {
+			t.selected[row] = true
+		}
+	}
+	t.notifySelection()
+}
+
+// SetSelectionCallback() sets the callback invoked with the selected rows (model indices) when the selection changes
+func (t *Table) SetSelectionCallback(callback func(selection []int)) {
+	t.callback = callback
+}
+
+// EnsureVisible() scrolls the table so that the given row (display index) is visible
+func (t *Table) EnsureVisible(index int) {
+	rowH := float32(t.RowHeight())
+	top := float32(index) * rowH
+	if top < t.scrollY {
+		t.setScroll(t.scrollX, top)
+	} else if top+rowH > t.scrollY+float32(t.bodyHeight()) {
+		t.setScroll(t.scrollX, top+rowH-float32(t.bodyHeight()))
+	}
+}
+
+// Edit() starts editing a cell (display row and column indices) if its column has an editor
+func (t *Table) Edit(index, column int) {
+	t.endEdit()
+	model, ok := t.model.(EditableTableModel)
+	if !ok || index < 0 || index >= len(t.order) || column < 0 || column >= len(t.columns) {
+		return
+	}
+	col := t.columns[column]
+	if col.Editor == nil {
+		return
+	}
+	t.EnsureVisible(index)
+	row := t.order[index]
+	var editor Widget
+	editor = col.Editor.CreateEditor(t, model.Value(row, col.Index), func(value interface{}) {
+		if t.editor != editor {
+			return
+		}
+		model.SetValue(row, col.Index, value)
+		t.endEdit()
+	})
+	t.editor = editor
+	t.editRow = row
+	t.editColumn = col
+	t.placeEditor()
+	editor.RequestFocus(editor)
+}
+
+func (t *Table) endEdit() {
+	if t.editor == nil {
+		return
+	}
+	editor := t.editor
+	t.editor = nil
+	t.editColumn = nil
+	if popupOwner, ok := editor.(interface{ PopupBaloon() *Popup }); ok {
+		popup := popupOwner.PopupBaloon()
+		popup.Parent().RemoveChild(popup)
+	}
+	t.RemoveChild(editor)
+}
+
+// placeEditor moves the editor over its cell
+func (t *Table) placeEditor() {
+	index := -1
+	for i, row := range t.order {
+		if row == t.editRow {
+			index = i
+			break
+		}
+	}
+	column := -1
+	for i, c := range t.columns {
+		if c == t.editColumn {
+			column = i
+		}
+	}
+	if index == -1 || column == -1 {
+		t.endEdit()
+		return
+	}
+	x := t.columnX(column)
+	y := t.RowHeight() + index*t.RowHeight() - int(t.scrollY)
+	w, h := t.editColumn.Width, t.RowHeight()
+	t.editor.SetPosition(x, y)
+	if ew, eh := t.editor.Size(); ew != w || eh != h {
+		t.editor.SetSize(w, h)
+		if screen := findScreen(t); screen != nil {
+			t.editor.OnPerformLayout(t.editor, screen.NVGContext())
+		}
+	}
+}
+
+func (t *Table) notifySelection() {
+	if t.callback != nil {
+		t.callback(t.Selection())
+	}
+}
+
+// selectIndex applies a click (or keyboard move) on a row (display index) to the selection
+func (t *Table) selectIndex(index int, modifier glfw.ModifierKey) {
+	t.current = index
+	if t.selectionMode == ListSelectionNone {
+		return
+	}
+	ctrl := modifier&(glfw.ModControl|glfw.ModSuper) != 0
+	shift := modifier&glfw.ModShift != 0
+	row := t.order[index]
+	if t.selectionMode == ListSelectionMultiple && shift && t.anchor != -1 {
+		if !ctrl {
+			t.selected = make(map[int]bool)
+		}
+		for i := minI(t.anchor, index); i <= maxI(t.anchor, index); i++ {
+			t.selected[t.order[i]] = true
+		}
+	} else if t.selectionMode == ListSelectionMultiple && ctrl {
+		if t.selected[row] {
+			delete(t.selected, row)
+		} else {
+			t.selected[row] = true
+		}
+		t.anchor = index
+	} else {
+		t.anchor = index
+		if len(t.selected) == 1 && t.selected[row] {
+			return
+		}
+		t.selected = map[int]bool{row: true}
+	}
+	t.notifySelection()
+}
+
+func (t *Table) contentWidth() int {
+	width := 0
+	for _, column := range t.columns {
+		width += column.Width
+	}
+	return width
+}
+
+func (t *Table) contentHeight() int {
+	return len(t.order) * t.RowHeight()
+}
+
+func (t *Table) bodyWidth() int {
+	if t.contentHeight() > t.h-t.RowHeight() {
+		return t.w - scrollBarSize
+	}
+	return t.w
+}
+
+func (t *Table) bodyHeight() int {
+	h := t.h - t.RowHeight()
+	if t.contentWidth() > t.w {
+		h -= scrollBarSize
+	}
+	return h
+}
+
+func (t *Table) maxScroll() (float32, float32) {
+	return maxF(0.0, float32(t.contentWidth()-t.bodyWidth())), maxF(0.0, float32(t.contentHeight()-t.bodyHeight()))
+}
+
+func (t *Table) setScroll(x, y float32) {
+	maxX, maxY := t.maxScroll()
+	t.scrollX = clampF(x, 0, maxX)
+	t.scrollY = clampF(y, 0, maxY)
+	if t.editor != nil {
+		t.placeEditor()
+	}
+}
+
+// columnX returns the left edge of a column (display index) relative to the table
+func (t *Table) columnX(column int) int {
+	x := -int(t.scrollX)
+	for _, c := range t.columns[:column] {
+		x += c.Width
+	}
+	return x
+}
+
+// columnAt returns the display index of the column at the given horizontal position (relative to the table) or -1
+func (t *Table) columnAt(x int) int {
+	cx := -int(t.scrollX)
+	for i, column := range t.columns {
+		if x >= cx && x < cx+column.Width {
+			return i
+		}
+		cx += column.Width
+	}
+	return -1
+}
+
+// resizeHandleAt returns the display index of the column whose right edge is at the given position or -1
+func (t *Table) resizeHandleAt(x int) int {
+	cx := -int(t.scrollX)
+	for i, column := range t.columns {
+		cx += column.Width
+		if x >= cx-4 && x <= cx+4 {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexAt() returns the display index of the row at the given position (parent coordinates) or -1
+func (t *Table) IndexAt(x, y int) int {
+	ly := y - t.y - t.RowHeight()
+	if !t.Contains(x, y) || ly < 0 || ly >= t.bodyHeight() {
+		return -1
+	}
+	index := int((float32(ly) + t.scrollY) / float32(t.RowHeight()))
+	if index < 0 || index >= len(t.order) {
+		return -1
+	}
+	return index
+}
+
+// scrollBarAt returns the axis of the scroll bar at the given position (parent coordinates) or -1
+func (t *Table) scrollBarAt(x, y int) int {
+	maxX, maxY := t.maxScroll()
+	lx := x - t.x
+	ly := y - t.y
+	if maxY > 0 && lx >= t.w-scrollBarSize && ly >= t.RowHeight() {
+		return 1
+	}
+	if maxX > 0 && ly >= t.h-scrollBarSize {
+		return 0
+	}
+	return -1
+}
+
+func (t *Table) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	return t.contentWidth() + scrollBarSize, t.RowHeight() * (clampI(t.RowCount(), 1, 10) + 1)
+}
+
+func (t *Table) FindWidget(self Widget, x, y int) Widget {
+	if t.scrollBarAt(x, y) != -1 {
+		return self
+	}
+	return t.WidgetImplement.FindWidget(self, x, y)
+}
+
+func (t *Table) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 && !down {
+		if t.dragAxis != -1 || t.resizeColumn != -1 {
+			t.dragAxis = -1
+			t.resizeColumn = -1
+			return true
+		}
+		if t.pressColumn != -1 {
+			column := t.pressColumn
+			t.pressColumn = -1
+			if t.moving {
+				t.moving = false
+				target := t.columnAt(x - t.x)
+				if target == -1 {
+					target = toI(x-t.x < 0, 0, len(t.columns)-1)
+				}
+				t.MoveColumn(column, target)
+			} else if t.columns[column].Sortable && t.columnAt(x-t.x) == column {
+				sortColumn, ascending := t.SortColumn()
+				t.SortBy(column, sortColumn != column || !ascending)
+			}
+			return true
+		}
+	}
+	if !t.enabled {
+		return false
+	}
+	if button == glfw.MouseButton1 && down {
+		if axis := t.scrollBarAt(x, y); axis != -1 {
+			t.dragAxis = axis
+			return true
+		}
+		if y-t.y < t.RowHeight() {
+			if !t.focused {
+				t.RequestFocus(self)
+			}
+			if column := t.resizeHandleAt(x - t.x); column != -1 {
+				t.resizeColumn = column
+			} else if column := t.columnAt(x - t.x); column != -1 {
+				t.pressColumn = column
+				t.pressX = x
+				t.moveX = x
+			}
+			return true
+		}
+	}
+	if t.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier) {
+		return true
+	}
+	if button == glfw.MouseButton1 && down {
+		index := t.IndexAt(x, y)
+		if index == -1 {
+			return true
+		}
+		now := GetTime()
+		if index == t.current && now-t.lastClick < 0.25 {
+			t.Edit(index, t.columnAt(x-t.x))
+		} else {
+			t.selectIndex(index, modifier)
+		}
+		t.lastClick = now
+		return true
+	}
+	return false
+}
+
+func (t *Table) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if t.resizeColumn != -1 {
+		column := t.columns[t.resizeColumn]
+		right := t.x + t.columnX(t.resizeColumn+1)
+		column.Width = maxI(column.MinWidth, column.Width+x-right)
+		t.setScroll(t.scrollX, t.scrollY)
+		return true
+	}
+	if t.pressColumn != -1 {
+		t.moveX = x
+		if absF(float32(x-t.pressX)) > 4 {
+			t.moving = true
+		}
+		return true
+	}
+	if t.dragAxis == -1 {
+		return false
+	}
+	maxX, maxY := t.maxScroll()
+	if t.dragAxis == 0 {
+		track := float32(t.bodyWidth() - 8)
+		_, size := scrollThumb(track, float32(t.bodyWidth()), float32(t.contentWidth()), 0)
+		if track > size {
+			t.setScroll(t.scrollX+float32(relX)*maxX/(track-size), t.scrollY)
+		}
+	} else {
+		track := float32(t.bodyHeight() - 8)
+		_, size := scrollThumb(track, float32(t.bodyHeight()), float32(t.contentHeight()), 0)
+		if track > size {
+			t.setScroll(t.scrollX, t.scrollY+float32(relY)*maxY/(track-size))
+		}
+	}
+	return true
+}
+
+func (t *Table) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	t.hover = t.IndexAt(x, y)
+	if y-t.y < t.RowHeight() && t.resizeHandleAt(x-t.x) != -1 {
+		t.cursor = HResize
+	} else {
+		t.cursor = Arrow
+	}
+	return t.WidgetImplement.MouseMotionEvent(self, x, y, relX, relY, button, modifier)
+}
+
+func (t *Table) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	t.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter {
+		t.hover = -1
+	}
+	return false
+}
+
+func (t *Table) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	if t.WidgetImplement.ScrollEvent(self, x, y, relX, relY) {
+		return true
+	}
+	if relX == 0 {
+		if screen := findScreen(self); screen != nil && screen.shiftPressed() {
+			relX, relY = relY, 0
+		}
+	}
+	maxX, maxY := t.maxScroll()
+	if maxX == 0 && maxY == 0 {
+		return false
+	}
+	t.setScroll(t.scrollX-float32(relX)*2, t.scrollY-float32(relY)*2)
+	t.hover = t.IndexAt(x, y)
+	return true
+}
+
+func (t *Table) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	count := len(t.order)
+	if !t.enabled || !t.focused || t.editor != nil || count == 0 || (action != glfw.Press && action != glfw.Repeat) {
+		return false
+	}
+	ctrl := modifier&(glfw.ModControl|glfw.ModSuper) != 0
+	index := t.current
+	page := maxI(1, t.bodyHeight()/t.RowHeight()-1)
+	switch key {
+	case glfw.KeyUp:
+		index--
+	case glfw.KeyDown:
+		index++
+	case glfw.KeyPageUp:
+		index -= page
+	case glfw.KeyPageDown:
+		index += page
+	case glfw.KeyHome:
+		index = 0
+	case glfw.KeyEnd:
+		index = count - 1
+	case glfw.KeyEnter, glfw.KeyF2:
+		if t.current != -1 {
+			for i, column := range t.columns {
+				if column.Editor != nil {
+					t.Edit(t.current, i)
+					break
+				}
+			}
+		}
+		return true
+	case glfw.KeyA:
+		if ctrl && t.selectionMode == ListSelectionMultiple {
+			t.selected = make(map[int]bool, count)
+			for _, row := range t.order {
+				t.selected[row] = true
+			}
+			t.notifySelection()
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+	index = clampI(index, 0, count-1)
+	if ctrl && modifier&glfw.ModShift == 0 {
+		t.current = index
+	} else {
+		t.selectIndex(index, modifier)
+	}
+	t.EnsureVisible(index)
+	return true
+}
+
+func (t *Table) Draw(self Widget, ctx *nanovgo.Context) {
+	if len(t.order) != t.RowCount() {
+		t.Refresh()
+	}
+	t.setScroll(t.scrollX, t.scrollY)
+
+	x := float32(t.x)
+	y := float32(t.y)
+	w := float32(t.w)
+	h := float32(t.h)
+	rowH := t.RowHeight()
+	bodyY := y + float32(rowH)
+	bodyW := float32(t.bodyWidth())
+	bodyH := float32(t.bodyHeight())
+
+	bg := nanovgo.BoxGradient(x+1, y+1, w-2, h-2, 3, 4, nanovgo.MONO(0, 32), nanovgo.MONO(0, 64))
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, h, 3)
+	ctx.SetFillPaint(bg)
+	ctx.Fill()
+
+	// rows
+	ctx.Save()
+	ctx.IntersectScissor(x, bodyY, bodyW, bodyH)
+	first := int(t.scrollY) / rowH
+	last := minI(len(t.order), (int(t.scrollY)+t.bodyHeight())/rowH+1)
+	for index := first; index < last; index++ {
+		row := t.order[index]
+		rowY := bodyY + float32(index*rowH) - t.scrollY
+		drawItemBackground(ctx, x, rowY, bodyW, float32(rowH), t.selected[row], index == t.hover && t.enabled,
+			index == t.current && t.focused)
+		cx := x - t.scrollX
+		for _, column := range t.columns {
+			cw := float32(column.Width)
+			if cx+cw >= x && cx <= x+bodyW && (t.editor == nil || column != t.editColumn || row != t.editRow) {
+				ctx.Save()
+				ctx.IntersectScissor(cx, rowY, cw, float32(rowH))
+				column.renderer().DrawCell(ctx, t, t.model.Value(row, column.Index), cx, rowY, cw, float32(rowH))
+				ctx.Restore()
+			}
+			cx += cw
+		}
+	}
+	// column separators
+	ctx.BeginPath()
+	cx := x - t.scrollX
+	for _, column := range t.columns {
+		cx += float32(column.Width)
+		ctx.MoveTo(floorF(cx)+0.5, bodyY)
+		ctx.LineTo(floorF(cx)+0.5, bodyY+bodyH)
+	}
+	ctx.SetStrokeWidth(1.0)
+	ctx.SetStrokeColor(t.theme.BorderDark)
+	ctx.Stroke()
+	t.WidgetImplement.Draw(self, ctx)
+	ctx.Restore()
+
+	// frozen header
+	ctx.Save()
+	ctx.IntersectScissor(x, y, w, float32(rowH))
+	headerPaint := nanovgo.LinearGradient(x, y, x, y+float32(rowH), t.theme.ButtonGradientTopUnfocused, t.theme.ButtonGradientBotUnfocused)
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, float32(rowH), 3)
+	ctx.SetFillPaint(headerPaint)
+	ctx.Fill()
+	cx = x - t.scrollX
+	for i, column := range t.columns {
+		cw := float32(column.Width)
+		hx := cx
+		if t.moving && i == t.pressColumn {
+			// the dragged header follows the mouse
+			hx += float32(t.moveX - t.pressX)
+			ctx.BeginPath()
+			ctx.Rect(hx, y, cw, float32(rowH))
+			ctx.SetFillColor(t.theme.ButtonGradientTopFocused)
+			ctx.Fill()
+		}
+		t.drawHeaderCell(ctx, column, hx, y, cw, float32(rowH))
+		cx += cw
+	}
+	ctx.Restore()
+
+	maxX, maxY := t.maxScroll()
+	if maxY > 0 {
+		track := bodyH - 8
+		offset, size := scrollThumb(track, bodyH, float32(t.contentHeight()), t.scrollY/maxY)
+		drawScrollBar(ctx, false, x+w-scrollBarSize, bodyY+4, 8, track, offset, size)
+	}
+	if maxX > 0 {
+		track := bodyW - 8
+		offset, size := scrollThumb(track, bodyW, float32(t.contentWidth()), t.scrollX/maxX)
+		drawScrollBar(ctx, true, x+4, y+h-scrollBarSize, track, 8, offset, size)
+	}
+}
+
+func (t *Table) drawHeaderCell(ctx *nanovgo.Context, column *TableColumn, x, y, w, h float32) {
+	ctx.Save()
+	ctx.IntersectScissor(x, y, w, h)
+	ctx.SetFontSize(float32(t.FontSize()))
+	ctx.SetFontFace(t.theme.FontBold)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	ctx.SetFillColor(t.theme.TextColor)
+	ctx.Text(x+4, y+h*0.5, column.Title)
+	if column == t.sortColumn {
+		icon := IconUpDir
+		if !t.sortAscending {
+			icon = IconDownDir
+		}
+		ctx.SetFontFace(t.theme.FontIcons)
+		ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
+		ctx.TextRune(x+w-4, y+h*0.5, []rune{rune(icon)})
+	}
+	ctx.Restore()
+
+	ctx.BeginPath()
+	ctx.MoveTo(floorF(x+w)-0.5, y+3)
+	ctx.LineTo(floorF(x+w)-0.5, y+h-3)
+	ctx.SetStrokeWidth(1.0)
+	ctx.SetStrokeColor(t.theme.BorderDark)
+	ctx.Stroke()
+}
+
+func (t *Table) String() string {
+	return t.StringHelper("Table", fmt.Sprintf("%d columns, %d rows", len(t.columns), t.RowCount()))
+}
+
+// lessCellValue compares two cell values: numbers sort first, then numeric strings (by value),
+// then text, booleans and the other values (by their printed form)
+func lessCellValue(a, b interface{}) bool {
+	rankA, numberA, textA := cellSortKey(a)
+	rankB, numberB, textB := cellSortKey(b)
+	switch {
+	case rankA != rankB:
+		return rankA < rankB
+	case rankA <= 1:
+		return numberA < numberB
+	}
+	return textA < textB
+}
+
+// cellSortKey returns the rank of the kind of a cell value, and the number or the text it is compared by
+func cellSortKey(v interface{}) (int, float64, string) {
+	var number float64
+	switch value := v.(type) {
+	case int:
+		number = float64(value)
+	case int8:
+		number = float64(value)
+	case int16:
+		number = float64(value)
+	case int32:
+		number = float64(value)
+	case int64:
+		number = float64(value)
+	case uint:
+		number = float64(value)
+	case uint8:
+		number = float64(value)
+	case uint16:
+		number = float64(value)
+	case uint32:
+		number = float64(value)
+	case uint64:
+		number = float64(value)
+	case float32:
+		number = float64(value)
+	case float64:
+		number = value
+	case string:
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(f) {
+			return 1, f, ""
+		}
+		return 2, 0, value
+	case bool:
+		if value {
+			return 3, 0, "1"
+		}
+		return 3, 0, "0"
+	default:
+		return 4, 0, fmt.Sprint(v)
+	}
+	if math.IsNaN(number) {
+		// NaN doesn't compare with the numbers
+		return 4, 0, "NaN"
+	}
+	return 0, number, ""
+}
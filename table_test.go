@@ -0,0 +1,75 @@
+package nanogui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLessCellValue(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{1, 2, true},
+		{2, 1, false},
+		{int8(-3), uint(2), true},
+		{float32(1.5), 2, true},
+		{"9", "10", true},
+		{"10", "9", false},
+		{"-1.5", "0", true},
+		{100, "1", true},
+		{"1", 100, false},
+		{"99", "a", true},
+		{"NaN", "b", true},
+		{"b", "a", false},
+		{"z", false, true},
+		{false, true, true},
+		{true, nil, true},
+		{math.NaN(), 1, false},
+		{1, math.NaN(), true},
+		{math.NaN(), math.NaN(), false},
+		{nil, math.NaN(), true},
+	}
+	for _, test := range tests {
+		if got := lessCellValue(test.a, test.b); got != test.want {
+			t.Errorf("lessCellValue(%#v, %#v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestLessCellValueOrdering(t *testing.T) {
+	values := []interface{}{"b", true, "10", math.NaN(), uint(7), "1a", struct{}{}, 3, "a", int8(-2), "-1", nil,
+		2.5, false, "NaN", "9", float32(0.5)}
+
+	// lessCellValue must be a strict weak ordering for sort to be consistent
+	for _, a := range values {
+		if lessCellValue(a, a) {
+			t.Errorf("lessCellValue(%#v, %#v) = true", a, a)
+		}
+		for _, b := range values {
+			if lessCellValue(a, b) && lessCellValue(b, a) {
+				t.Errorf("lessCellValue(%#v, %#v) and lessCellValue(%#v, %#v) are both true", a, b, b, a)
+			}
+			for _, c := range values {
+				if lessCellValue(a, b) && lessCellValue(b, c) && !lessCellValue(a, c) {
+					t.Errorf("lessCellValue is not transitive for %#v < %#v < %#v", a, b, c)
+				}
+			}
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return lessCellValue(values[i], values[j])
+	})
+	var sorted []string
+	for _, value := range values {
+		sorted = append(sorted, fmt.Sprint(value))
+	}
+	want := "-2 0.5 2.5 3 7 -1 9 10 1a NaN a b false true <nil> NaN {}"
+	if got := strings.Join(sorted, " "); got != want {
+		t.Errorf("sorted values = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,256 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+// Tab widget
+//
+// TabWidget pairs a TabHeader with a stack of pages: each tab owns one page
+// and only the page of the active tab is shown. Ctrl+Tab and Ctrl+Shift+Tab
+// cycle through the tabs while the tab widget (or one of its pages) has the
+// focus.
+type TabWidget struct {
+	WidgetImplement
+
+	header        *TabHeader
+	pages         []Widget
+	callback      func(index int)
+	closeCallback func(index int) bool
+}
+
+func NewTabWidget(parent Widget) *TabWidget {
+	tabWidget := &TabWidget{}
+	InitWidget(tabWidget, parent)
+	tabWidget.header = NewTabHeader(tabWidget)
+	tabWidget.header.SetCallback(func(index int) {
+		tabWidget.showPage(index)
+		if tabWidget.callback != nil {
+			tabWidget.callback(index)
+		}
+	})
+	tabWidget.header.SetCloseCallback(func(index int) {
+		if tabWidget.closeCallback == nil || tabWidget.closeCallback(index) {
+			tabWidget.RemoveTab(index)
+		}
+	})
+	tabWidget.header.SetReorderCallback(func(from, to int) {
+		page := tabWidget.pages[from]
+		if from < to {
+			copy(tabWidget.pages[from:], tabWidget.pages[from+1:to+1])
+		} else {
+			copy(tabWidget.pages[to+1:], tabWidget.pages[to:from])
+		}
+		tabWidget.pages[to] = page
+	})
+	return tabWidget
+}
+
+// Header() returns the header showing the tabs
+func (t *TabWidget) Header() *TabHeader {
+	return t.header
+}
+
+// SetCallback() sets the callback invoked when the active tab changes
+func (t *TabWidget) SetCallback(callback func(index int)) {
+	t.callback = callback
+}
+
+// SetCloseCallback() sets the callback invoked when the close button of a tab is clicked.
+// The tab is removed only if the callback returns true.
+func (t *TabWidget) SetCloseCallback(callback func(index int) bool) {
+	t.closeCallback = callback
+}
+
+// SetRemoveCallback() sets the callback invoked after a tab and its page have been removed
+func (t *TabWidget) SetRemoveCallback(callback func(index int)) {
+	t.header.SetRemoveCallback(callback)
+}
+
+func (t *TabWidget) TabCount() int {
+	return len(t.pages)
+}
+
+func (t *TabWidget) ActiveTab() int {
+	return t.header.ActiveTab()
+}
+
+func (t *TabWidget) SetActiveTab(index int) {
+	t.header.SetActiveTab(index)
+}
+
+// CreateTab() adds a new tab with an empty page and returns the page
+func (t *TabWidget) CreateTab(label string) Widget {
+	page := NewWidget(t)
+	t.AddTab(label, page)
+	return page
+}
+
+// AddTab() appends a tab showing the given page; the page becomes a child of the tab widget
+func (t *TabWidget) AddTab(label string, page Widget) {
+	t.InsertTab(len(t.pages), label, page)
+}
+
+// InsertTab() inserts a tab showing the given page at the given index and activates it
+func (t *TabWidget) InsertTab(index int, label string, page Widget) {
+	index = clampI(index, 0, len(t.pages))
+	if parent := page.Parent(); parent != Widget(t) {
+		if parent != nil {
+			parent.RemoveChild(page)
+		}
+		t.AddChild(t, page)
+	}
+	t.pages = append(t.pages, nil)
+	copy(t.pages[index+1:], t.pages[index:])
+	t.pages[index] = page
+	t.header.AddTab(index, label)
+}
+
+// RemoveTab() removes a tab and its page
+func (t *TabWidget) RemoveTab(index int) {
+	if index < 0 || index >= len(t.pages) {
+		return
+	}
+	t.RemoveChild(t.pages[index])
+	copy(t.pages[index:], t.pages[index+1:])
+	t.pages[len(t.pages)-1] = nil
+	t.pages = t.pages[:len(t.pages)-1]
+	t.header.RemoveTab(index)
+}
+
+// Tab() returns the page of the tab at the given index
+func (t *TabWidget) Tab(index int) Widget {
+	if index < 0 || index >= len(t.pages) {
+		return nil
+	}
+	return t.pages[index]
+}
+
+// TabIndex() returns the index of the tab showing the given page, or -1
+func (t *TabWidget) TabIndex(page Widget) int {
+	for i, p := range t.pages {
+		if p == page {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *TabWidget) TabLabel(index int) string {
+	return t.header.TabLabelAt(index)
+}
+
+func (t *TabWidget) SetTabLabel(index int, label string) {
+	t.header.SetTabLabel(index, label)
+}
+
+// SetTabIcon() sets the icon shown before the label of a tab (0 removes it)
+func (t *TabWidget) SetTabIcon(index int, icon Icon) {
+	t.header.SetTabIcon(index, icon)
+}
+
+// SetTabClosable() sets whether a tab shows a close button
+func (t *TabWidget) SetTabClosable(index int, closable bool) {
+	t.header.SetTabClosable(index, closable)
+}
+
+func (t *TabWidget) showPage(index int) {
+	for i, page := range t.pages {
+		page.SetVisible(i == index)
+	}
+	t.InvalidateLayout()
+}
+
+func (t *TabWidget) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	padding := t.Padding()
+	_, headerHeight := t.header.PreferredSize(t.header, ctx)
+	margin := t.theme.TabInnerMargin
+
+	t.header.SetPosition(padding.Left, padding.Top)
+	t.header.SetSize(t.w-padding.Horizontal(), headerHeight)
+	t.header.OnPerformLayout(t.header, ctx)
+
+	x := padding.Left + margin
+	y := padding.Top + headerHeight + 1 + margin
+	w := t.w - padding.Horizontal() - 2*margin
+	h := t.h - padding.Vertical() - headerHeight - 1 - 2*margin
+	for _, page := range t.pages {
+		if !page.Visible() {
+			continue
+		}
+		PlaceWidget(page, x, y, w, h)
+		page.OnPerformLayout(page, ctx)
+	}
+}
+
+func (t *TabWidget) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	padding := t.Padding()
+	margin := t.theme.TabInnerMargin
+	headerWidth, headerHeight := t.header.PreferredSize(t.header, ctx)
+	w, h := 0, 0
+	for _, page := range t.pages {
+		size := OuterSize(page, ctx)
+		w = maxI(w, size[0])
+		h = maxI(h, size[1])
+	}
+	w = maxI(headerWidth, w+2*margin)
+	return w + padding.Horizontal(), headerHeight + 1 + h + 2*margin + padding.Vertical()
+}
+
+func (t *TabWidget) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !t.enabled || action == glfw.Release || key != glfw.KeyTab || modifier&glfw.ModControl == 0 || len(t.pages) == 0 {
+		return false
+	}
+	step := 1
+	if modifier&glfw.ModShift != 0 {
+		step = len(t.pages) - 1
+	}
+	t.SetActiveTab((t.ActiveTab() + step) % len(t.pages))
+	return true
+}
+
+func (t *TabWidget) Draw(self Widget, ctx *nanovgo.Context) {
+	padding := t.Padding()
+	x := float32(t.x + padding.Left)
+	y := float32(t.y + padding.Top)
+	w := float32(t.w - padding.Horizontal())
+	h := float32(t.h - padding.Vertical())
+	tabHeight := float32(t.header.Height())
+	start, end, ok := t.header.activeButtonArea()
+
+	// the border is drawn in pieces to leave a gap below the active tab
+	for i := 0; i < 3; i++ {
+		if !ok && i < 2 {
+			continue
+		}
+		ctx.Save()
+		switch i {
+		case 0:
+			ctx.IntersectScissor(x, y, float32(start)+1, h)
+		case 1:
+			ctx.IntersectScissor(x+float32(end), y, w-float32(end), h)
+		default:
+			if ok {
+				ctx.IntersectScissor(x, y+tabHeight+2, w, h)
+			}
+		}
+		ctx.BeginPath()
+		ctx.SetStrokeWidth(1.0)
+		ctx.RoundedRect(x+0.5, y+tabHeight+1.5, w-1, h-tabHeight-2, float32(t.theme.ButtonCornerRadius))
+		ctx.SetStrokeColor(t.theme.BorderLight)
+		ctx.Stroke()
+
+		ctx.BeginPath()
+		ctx.RoundedRect(x+0.5, y+tabHeight+0.5, w-1, h-tabHeight-2, float32(t.theme.ButtonCornerRadius))
+		ctx.SetStrokeColor(t.theme.BorderDark)
+		ctx.Stroke()
+		ctx.Restore()
+	}
+
+	t.WidgetImplement.Draw(self, ctx)
+}
+
+func (t *TabWidget) String() string {
+	return t.StringHelper("TabWidget", "")
+}
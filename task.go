@@ -0,0 +1,67 @@
+package nanogui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"sync"
+)
+
+var taskMutex sync.Mutex
+var tasks []func()
+var tasksAccepted bool
+
+// Post() queues a function to run on the UI thread at the start of the next frame
+//
+// Widgets must only be changed from the UI thread (the one calling Init() and
+// MainLoop()); Post() can be called from any goroutine. While MainLoop() is
+// running it is woken up if it is waiting for events; otherwise the function
+// waits in the queue until MainLoop() is started.
+func Post(task func()) {
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	tasks = append(tasks, task)
+	if tasksAccepted {
+		// GLFW is initialized while MainLoop() runs, and it can't return before the lock is released
+		glfw.PostEmptyEvent()
+	}
+}
+
+// Invoke() runs a function on the UI thread and waits until it has completed
+//
+// It can only be used while MainLoop() is running, and panics otherwise.
+// Invoke() must never be called from the UI thread itself (e.g. from a
+// widget callback or a posted task): it would wait for the loop it blocks,
+// forever. Use Post() there, or call the function directly.
+func Invoke(task func()) {
+	taskMutex.Lock()
+	if !tasksAccepted {
+		taskMutex.Unlock()
+		panic("Invoke: MainLoop() is not running")
+	}
+	done := make(chan struct{})
+	tasks = append(tasks, func() {
+		defer close(done)
+		task()
+	})
+	glfw.PostEmptyEvent()
+	taskMutex.Unlock()
+	<-done
+}
+
+// acceptTasks marks the start (or the end) of MainLoop(), which wakes up when a task is posted
+func acceptTasks(accept bool) {
+	taskMutex.Lock()
+	tasksAccepted = accept
+	taskMutex.Unlock()
+}
+
+// runTasks runs the functions queued so far and returns whether there were any
+func runTasks() bool {
+	taskMutex.Lock()
+	queue := tasks
+	tasks = nil
+	taskMutex.Unlock()
+	for _, task := range queue {
+		task()
+	}
+	return len(queue) > 0
+}
@@ -89,6 +89,7 @@ func (t *TextBox) Value() string {
 
 func (t *TextBox) SetValue(value string) {
 	t.value = value
+	t.InvalidateLayout()
 }
 
 func (t *TextBox) DefaultValue() string {
@@ -59,10 +59,16 @@ type Theme struct {
 	FontIcons  string
 }
 
+// NewStandardTheme() returns a new standard theme, registering the shared fonts into the context
+//
+// Screens use the theme shared through Resources(); a new theme is only
+// needed to style a part of the UI differently.
 func NewStandardTheme(ctx *nanovgo.Context) *Theme {
-	ctx.CreateFontFromMemory("sans", MustAsset("fonts/Roboto-Regular.ttf"), 0)
-	ctx.CreateFontFromMemory("sans-bold", MustAsset("fonts/Roboto-Bold.ttf"), 0)
-	ctx.CreateFontFromMemory("icons", MustAsset("fonts/entypo.ttf"), 0)
+	resources.addContext(ctx)
+	return standardTheme()
+}
+
+func standardTheme() *Theme {
 	return &Theme{
 		StandardFontSize:     16,
 		ButtonFontSize:       20,
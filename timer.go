@@ -0,0 +1,115 @@
+package nanogui
+
+// Timer calls a function on the UI thread once or repeatedly (see AfterFunc() and Every())
+//
+// Timers are run by MainLoop, which sleeps until the next deadline when no
+// events arrive. They must be created and stopped on the UI thread; use
+// Post() from other goroutines.
+type Timer struct {
+	deadline float32
+	interval float32
+	callback func()
+	active   bool
+}
+
+var timers []*Timer
+
+// AfterFunc() calls the function once after the delay (in seconds)
+func AfterFunc(delay float32, callback func()) *Timer {
+	timer := &Timer{
+		callback: callback,
+	}
+	timer.Reset(delay)
+	return timer
+}
+
+// Every() calls the function repeatedly with the given interval (in seconds)
+func Every(interval float32, callback func()) *Timer {
+	timer := &Timer{
+		interval: interval,
+		callback: callback,
+	}
+	timer.Reset(interval)
+	return timer
+}
+
+// Active() returns whether the timer will fire again
+func (t *Timer) Active() bool {
+	return t.active
+}
+
+// Interval() returns the interval of a repeating timer (0 for one-shot timers)
+func (t *Timer) Interval() float32 {
+	return t.interval
+}
+
+// Deadline() returns the time (see GetTime()) the timer fires next
+func (t *Timer) Deadline() float32 {
+	return t.deadline
+}
+
+// Stop() cancels the timer; it returns false if the timer was not active
+func (t *Timer) Stop() bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, timer := range timers {
+		if timer == t {
+			timers = append(timers[:i], timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Reset() (re)starts the timer so that it fires after the delay (in seconds)
+func (t *Timer) Reset(delay float32) {
+	t.deadline = GetTime() + delay
+	if !t.active {
+		t.active = true
+		timers = append(timers, t)
+	}
+}
+
+// runTimers calls the timers whose deadline has passed and returns whether there were any
+func runTimers() bool {
+	if len(timers) == 0 {
+		return false
+	}
+	now := FrameTime()
+	var due []*Timer
+	for _, timer := range timers {
+		if timer.deadline <= now {
+			due = append(due, timer)
+		}
+	}
+	for _, timer := range due {
+		// a previous callback may have stopped or reset it
+		if !timer.active || timer.deadline > now {
+			continue
+		}
+		if timer.interval > 0 {
+			timer.deadline += timer.interval
+			if timer.deadline <= now {
+				// skip the missed deadlines instead of firing in a burst
+				timer.deadline = now + timer.interval
+			}
+		} else {
+			timer.Stop()
+		}
+		timer.callback()
+	}
+	return len(due) > 0
+}
+
+// nextTimerDeadline returns the earliest deadline of the active timers (0 if none)
+func nextTimerDeadline() float32 {
+	var next float32
+	for _, timer := range timers {
+		if next == 0 || timer.deadline < next {
+			next = timer.deadline
+		}
+	}
+	return next
+}
@@ -0,0 +1,132 @@
+package nanogui
+
+import (
+	"testing"
+	"time"
+)
+
+// resetTimers removes the timers left by a previous test; GetTime() counts from now
+func resetTimers() {
+	timers = nil
+	startTime = time.Now()
+}
+
+func TestNextTimerDeadline(t *testing.T) {
+	resetTimers()
+	if next := nextTimerDeadline(); next != 0 {
+		t.Errorf("nextTimerDeadline() = %v without timers, want 0", next)
+	}
+	later := AfterFunc(2, func() {})
+	repeating := Every(0.5, func() {})
+	soon := AfterFunc(1, func() {})
+
+	tests := []struct {
+		name   string
+		change func()
+		want   *Timer
+	}{
+		{"earliest timer", func() {}, repeating},
+		{"stopped timer", func() { repeating.Stop() }, soon},
+		{"reset timer", func() { later.Reset(0.1) }, later},
+		{"restarted timer", func() { repeating.Reset(0) }, repeating},
+	}
+	for _, test := range tests {
+		test.change()
+		if next := nextTimerDeadline(); next != test.want.Deadline() {
+			t.Errorf("%s: nextTimerDeadline() = %v, want %v", test.name, next, test.want.Deadline())
+		}
+	}
+
+	later.Stop()
+	soon.Stop()
+	repeating.Stop()
+	if next := nextTimerDeadline(); next != 0 {
+		t.Errorf("nextTimerDeadline() = %v after stopping the timers, want 0", next)
+	}
+}
+
+func TestRunTimers(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  float32
+		overdue   float32 // how long ago the deadline passed (negative: not due yet)
+		wantRun   bool
+		wantCalls int
+		wantNext  func(before, after, deadline float32) bool
+	}{
+		{"one-shot not due", 0, -10, false, 0, nil},
+		{"one-shot due", 0, 0.5, true, 1, nil},
+		{"repeating not due", 1, -0.5, false, 0, func(before, after, deadline float32) bool {
+			return deadline > after
+		}},
+		{"repeating due", 1, 0.25, true, 1, func(before, after, deadline float32) bool {
+			// the next deadline keeps the period
+			return deadline > before+0.5 && deadline <= after+0.75
+		}},
+		{"repeating with missed deadlines", 1, 10, true, 1, func(before, after, deadline float32) bool {
+			// the missed deadlines are skipped: the next one is an interval from now
+			return deadline >= before+1 && deadline <= after+1
+		}},
+	}
+	for _, test := range tests {
+		resetTimers()
+		calls := 0
+		var timer *Timer
+		if test.interval > 0 {
+			timer = Every(test.interval, func() { calls++ })
+		} else {
+			timer = AfterFunc(1, func() { calls++ })
+		}
+		timer.deadline = GetTime() - test.overdue
+
+		before := GetTime()
+		run := runTimers()
+		after := GetTime()
+		if run != test.wantRun || calls != test.wantCalls {
+			t.Errorf("%s: runTimers() = %v with %d calls, want %v with %d calls", test.name, run, calls, test.wantRun, test.wantCalls)
+		}
+		wantActive := test.interval > 0 || !test.wantRun
+		if timer.Active() != wantActive || (len(timers) == 1) != wantActive {
+			t.Errorf("%s: Active() = %v with %d timers, want %v", test.name, timer.Active(), len(timers), wantActive)
+		}
+		if test.wantNext != nil && !test.wantNext(before, after, timer.Deadline()) {
+			t.Errorf("%s: the next deadline is %v (runTimers() ran between %v and %v)", test.name, timer.Deadline(), before, after)
+		}
+	}
+	resetTimers()
+}
+
+func TestRunTimersFromCallbacks(t *testing.T) {
+	resetTimers()
+	var fired []string
+	var first, second, third *Timer
+	first = AfterFunc(1, func() {
+		fired = append(fired, "first")
+		second.Stop()
+		// a one-shot timer can restart itself
+		first.Reset(5)
+	})
+	second = AfterFunc(1, func() {
+		fired = append(fired, "second")
+	})
+	third = AfterFunc(1, func() {
+		fired = append(fired, "third")
+	})
+	for _, timer := range []*Timer{first, second, third} {
+		timer.deadline = GetTime() - 1
+	}
+
+	if !runTimers() {
+		t.Errorf("runTimers() = false with due timers")
+	}
+	if len(fired) != 2 || fired[0] != "first" || fired[1] != "third" {
+		t.Errorf("the timers fired are %v, want [first third]", fired)
+	}
+	if !first.Active() || second.Active() || third.Active() {
+		t.Errorf("Active() = %v, %v, %v, want true, false, false", first.Active(), second.Active(), third.Active())
+	}
+	if next := nextTimerDeadline(); next != first.Deadline() || next <= GetTime() {
+		t.Errorf("nextTimerDeadline() = %v, want the restarted timer deadline %v", next, first.Deadline())
+	}
+	resetTimers()
+}
@@ -0,0 +1,576 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/maxfish/vg4go-gl4"
+)
+
+// TreeNode is a node displayed by a TreeView
+type TreeNode struct {
+	Caption string
+	Icon    Icon
+	Data    interface{}
+
+	tree        *TreeView
+	parent      *TreeNode
+	children    []*TreeNode
+	expanded    bool
+	loaded      bool
+	hasChildren bool
+}
+
+func NewTreeNode(caption string, icons ...Icon) *TreeNode {
+	var icon Icon
+	switch len(icons) {
+	case 0:
+	case 1:
+		icon = icons[0]
+	default:
+		panic("NewTreeNode can accept only one extra parameter (icon)")
+	}
+	return &TreeNode{
+		Caption: caption,
+		Icon:    icon,
+		loaded:  true,
+	}
+}
+
+// Parent() returns the parent node (nil for the root node)
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Children() returns the child nodes
+func (n *TreeNode) Children() []*TreeNode {
+	return n.children
+}
+
+// AddChild() appends a child node and returns it
+func (n *TreeNode) AddChild(child *TreeNode) *TreeNode {
+	if child.parent != nil {
+		child.parent.RemoveChild(child)
+	}
+	n.children = append(n.children, child)
+	n.loaded = true
+	child.parent = n
+	child.setTree(n.tree)
+	n.invalidate()
+	return child
+}
+
+// RemoveChild() removes a child node
+func (n *TreeNode) RemoveChild(child *TreeNode) {
+	for i, c := range n.children {
+		if c == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			if tree := n.tree; tree != nil && tree.selected != nil && tree.selected.IsDescendantOf(child) {
+				tree.SetSelectedNode(nil)
+			}
+			child.parent = nil
+			child.setTree(nil)
+			n.invalidate()
+			return
+		}
+	}
+}
+
+// ClearChildren() removes all the child nodes
+func (n *TreeNode) ClearChildren() {
+	for len(n.children) > 0 {
+		n.RemoveChild(n.children[len(n.children)-1])
+	}
+}
+
+// HasChildren() returns whether the node can be expanded
+func (n *TreeNode) HasChildren() bool {
+	return len(n.children) > 0 || (n.hasChildren && !n.loaded)
+}
+
+// SetHasChildren() marks the node as expandable before its children are loaded;
+// they are requested from the load callback of the TreeView when the node is expanded for the first time
+func (n *TreeNode) SetHasChildren(flag bool) {
+	n.hasChildren = flag
+	n.loaded = !flag
+	n.invalidate()
+}
+
+// Expanded() returns whether the children of the node are shown
+func (n *TreeNode) Expanded() bool {
+	return n.expanded
+}
+
+// SetExpanded() shows or hides the children of the node, loading them if necessary
+func (n *TreeNode) SetExpanded(flag bool) {
+	if flag == n.expanded {
+		return
+	}
+	n.expanded = flag
+	if flag && !n.loaded {
+		n.loaded = true
+		if n.tree != nil && n.tree.loadCallback != nil {
+			n.tree.loadCallback(n)
+		}
+	}
+	if n.tree != nil && n.tree.expandCallback != nil {
+		n.tree.expandCallback(n, flag)
+	}
+	n.invalidate()
+}
+
+// Depth() returns the number of ancestors of the node, not counting the root node
+func (n *TreeNode) Depth() int {
+	depth := -1
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// IsDescendantOf() returns whether the node is the given node or one of its descendants
+func (n *TreeNode) IsDescendantOf(node *TreeNode) bool {
+	for p := n; p != nil; p = p.parent {
+		if p == node {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *TreeNode) setTree(tree *TreeView) {
+	n.tree = tree
+	for _, child := range n.children {
+		child.setTree(tree)
+	}
+}
+
+func (n *TreeNode) invalidate() {
+	if n.tree != nil {
+		n.tree.rowsDirty = true
+	}
+}
+
+// Tree view widget
+//
+// TreeView shows a hierarchy of TreeNode values. Nodes are expanded with a
+// click on their chevron, a double click or the Left/Right keys. Children can
+// be loaded lazily from a callback. The tree is flattened into rows of a fixed
+// height and only the visible rows are drawn, so very large trees remain
+// responsive.
+type TreeView struct {
+	WidgetImplement
+
+	root           *TreeNode
+	rows           []*TreeNode
+	rowsDirty      bool
+	itemHeight     int
+	indent         int
+	showGuides     bool
+	selected       *TreeNode
+	hover          *TreeNode
+	scrollPosition float32
+	dragScroll     bool
+	lastClick      float32
+	callback       func(*TreeNode)
+	loadCallback   func(*TreeNode)
+	expandCallback func(*TreeNode, bool)
+}
+
+func NewTreeView(parent Widget) *TreeView {
+	tree := &TreeView{
+		indent:     16,
+		showGuides: true,
+	}
+	tree.root = &TreeNode{
+		tree:     tree,
+		expanded: true,
+		loaded:   true,
+	}
+	InitWidget(tree, parent)
+	return tree
+}
+
+// Root() returns the invisible root node; its children are the top level nodes of the tree
+func (t *TreeView) Root() *TreeNode {
+	return t.root
+}
+
+// ItemHeight() returns the height of a row
+func (t *TreeView) ItemHeight() int {
+	if t.itemHeight > 0 {
+		return t.itemHeight
+	}
+	return t.FontSize() + 8
+}
+
+// SetItemHeight() sets the height of a row (0: derived from the font size)
+func (t *TreeView) SetItemHeight(h int) {
+	t.itemHeight = h
+}
+
+// Indent() returns the horizontal offset between a node and its children
+func (t *TreeView) Indent() int {
+	return t.indent
+}
+
+// SetIndent() sets the horizontal offset between a node and its children
+func (t *TreeView) SetIndent(indent int) {
+	t.indent = indent
+}
+
+// ShowGuides() returns whether indentation guides are drawn
+func (t *TreeView) ShowGuides() bool {
+	return t.showGuides
+}
+
+// SetShowGuides() sets whether indentation guides are drawn
+func (t *TreeView) SetShowGuides(flag bool) {
+	t.showGuides = flag
+}
+
+// SelectedNode() returns the selected node (nil if none)
+func (t *TreeView) SelectedNode() *TreeNode {
+	return t.selected
+}
+
+// SetSelectedNode() selects a node, expanding its ancestors and scrolling to it
+func (t *TreeView) SetSelectedNode(node *TreeNode) {
+	if node == t.selected {
+		return
+	}
+	t.selected = node
+	if node != nil {
+		t.EnsureVisible(node)
+	}
+	if t.callback != nil {
+		t.callback(node)
+	}
+}
+
+// SetSelectionCallback() sets the callback invoked when the selected node changes
+func (t *TreeView) SetSelectionCallback(callback func(node *TreeNode)) {
+	t.callback = callback
+}
+
+// SetLoadCallback() sets the callback used to populate a node marked with SetHasChildren() on first expansion
+func (t *TreeView) SetLoadCallback(callback func(node *TreeNode)) {
+	t.loadCallback = callback
+}
+
+// SetExpandCallback() sets the callback invoked when a node is expanded or collapsed
+func (t *TreeView) SetExpandCallback(callback func(node *TreeNode, expanded bool)) {
+	t.expandCallback = callback
+}
+
+// EnsureVisible() expands the ancestors of a node and scrolls the tree so that it is visible
+func (t *TreeView) EnsureVisible(node *TreeNode) {
+	for p := node.parent; p != nil && p != t.root; p = p.parent {
+		p.SetExpanded(true)
+	}
+	index := t.rowIndex(node)
+	if index == -1 {
+		return
+	}
+	rowH := float32(t.ItemHeight())
+	top := float32(index) * rowH
+	if top < t.scrollPosition {
+		t.setScrollPosition(top)
+	} else if top+rowH > t.scrollPosition+float32(t.h) {
+		t.setScrollPosition(top + rowH - float32(t.h))
+	}
+}
+
+// NodeAt() returns the node at the given position (parent coordinates) or nil
+func (t *TreeView) NodeAt(x, y int) *TreeNode {
+	if !t.Contains(x, y) {
+		return nil
+	}
+	rows := t.visibleRows()
+	index := int((float32(y-t.y) + t.scrollPosition) / float32(t.ItemHeight()))
+	if index < 0 || index >= len(rows) {
+		return nil
+	}
+	return rows[index]
+}
+
+// visibleRows returns the flattened list of the nodes whose ancestors are all expanded
+func (t *TreeView) visibleRows() []*TreeNode {
+	if t.rowsDirty || t.rows == nil {
+		t.rows = t.rows[:0]
+		t.rows = appendTreeRows(t.rows, t.root)
+		t.rowsDirty = false
+	}
+	return t.rows
+}
+
+func appendTreeRows(rows []*TreeNode, node *TreeNode) []*TreeNode {
+	for _, child := range node.children {
+		rows = append(rows, child)
+		if child.expanded {
+			rows = appendTreeRows(rows, child)
+		}
+	}
+	return rows
+}
+
+func (t *TreeView) rowIndex(node *TreeNode) int {
+	for i, row := range t.visibleRows() {
+		if row == node {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *TreeView) contentHeight() int {
+	return len(t.visibleRows()) * t.ItemHeight()
+}
+
+func (t *TreeView) setScrollPosition(position float32) {
+	t.scrollPosition = clampF(position, 0.0, maxF(0.0, float32(t.contentHeight()-t.h)))
+}
+
+func (t *TreeView) scrollBarVisible() bool {
+	return t.contentHeight() > t.h
+}
+
+// thumbRect returns the offset and length of the scroll bar thumb along its track
+func (t *TreeView) thumbRect() (float32, float32) {
+	var scroll float32
+	if maxScroll := float32(t.contentHeight() - t.h); maxScroll > 0 {
+		scroll = t.scrollPosition / maxScroll
+	}
+	return scrollThumb(float32(t.h-8), float32(t.h), float32(t.contentHeight()), scroll)
+}
+
+func (t *TreeView) overScrollBar(x, y int) bool {
+	return t.scrollBarVisible() && t.Contains(x, y) && x >= t.x+t.w-scrollBarSize
+}
+
+// chevronX returns the horizontal offset of the expand chevron of a node, relative to the widget
+func (t *TreeView) chevronX(node *TreeNode) int {
+	return 4 + node.Depth()*t.indent
+}
+
+func (t *TreeView) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+	ctx.SetFontSize(float32(t.FontSize()))
+	ctx.SetFontFace(t.theme.FontNormal)
+	width := 100
+	rows := t.visibleRows()
+	for _, node := range rows[:minI(len(rows), 100)] {
+		w, _ := ctx.TextBounds(0, 0, node.Caption)
+		width = maxI(width, t.chevronX(node)+t.indent*2+int(w)+8)
+	}
+	return width + scrollBarSize, t.ItemHeight() * clampI(len(rows), 1, 10)
+}
+
+func (t *TreeView) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button != glfw.MouseButton1 {
+		return false
+	}
+	if !down {
+		t.dragScroll = false
+		return true
+	}
+	if t.overScrollBar(x, y) {
+		t.dragScroll = true
+		return true
+	}
+	if !t.enabled {
+		return false
+	}
+	if !t.focused {
+		t.RequestFocus(self)
+	}
+	node := t.NodeAt(x, y)
+	if node == nil {
+		return true
+	}
+	now := GetTime()
+	cx := t.x + t.chevronX(node)
+	if node.HasChildren() && x >= cx && x < cx+t.indent {
+		node.SetExpanded(!node.expanded)
+	} else if node == t.selected && now-t.lastClick < 0.25 {
+		node.SetExpanded(!node.expanded)
+	}
+	t.lastClick = now
+	t.SetSelectedNode(node)
+	return true
+}
+
+func (t *TreeView) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	t.hover = t.NodeAt(x, y)
+	return false
+}
+
+func (t *TreeView) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	t.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+	if !enter {
+		t.hover = nil
+	}
+	return false
+}
+
+func (t *TreeView) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if !t.dragScroll {
+		return false
+	}
+	_, size := t.thumbRect()
+	if track := float32(t.h-8) - size; track > 0 {
+		t.setScrollPosition(t.scrollPosition + float32(relY)*float32(t.contentHeight()-t.h)/track)
+	}
+	return true
+}
+
+func (t *TreeView) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	if !t.scrollBarVisible() {
+		return false
+	}
+	t.setScrollPosition(t.scrollPosition - float32(relY)*2)
+	t.hover = t.NodeAt(x, y)
+	return true
+}
+
+func (t *TreeView) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	rows := t.visibleRows()
+	if !t.enabled || !t.focused || len(rows) == 0 || (action != glfw.Press && action != glfw.Repeat) {
+		return false
+	}
+	index := -1
+	if t.selected != nil {
+		index = t.rowIndex(t.selected)
+	}
+	page := maxI(1, t.h/t.ItemHeight()-1)
+	switch key {
+	case glfw.KeyUp:
+		index--
+	case glfw.KeyDown:
+		index++
+	case glfw.KeyPageUp:
+		index -= page
+	case glfw.KeyPageDown:
+		index += page
+	case glfw.KeyHome:
+		index = 0
+	case glfw.KeyEnd:
+		index = len(rows) - 1
+	case glfw.KeyLeft:
+		if t.selected == nil {
+			return true
+		}
+		if t.selected.expanded && t.selected.HasChildren() {
+			t.selected.SetExpanded(false)
+		} else if t.selected.parent != t.root {
+			t.SetSelectedNode(t.selected.parent)
+		}
+		return true
+	case glfw.KeyRight:
+		if t.selected == nil || !t.selected.HasChildren() {
+			return true
+		}
+		if !t.selected.expanded {
+			t.selected.SetExpanded(true)
+		} else if len(t.selected.children) > 0 {
+			t.SetSelectedNode(t.selected.children[0])
+		}
+		return true
+	case glfw.KeySpace, glfw.KeyEnter:
+		if t.selected != nil && t.selected.HasChildren() {
+			t.selected.SetExpanded(!t.selected.expanded)
+		}
+		return true
+	default:
+		return false
+	}
+	t.SetSelectedNode(rows[clampI(index, 0, len(rows)-1)])
+	return true
+}
+
+func (t *TreeView) Draw(self Widget, ctx *nanovgo.Context) {
+	t.WidgetImplement.Draw(self, ctx)
+
+	x := float32(t.x)
+	y := float32(t.y)
+	w := float32(t.w)
+	h := float32(t.h)
+
+	bg := nanovgo.BoxGradient(x+1, y+1, w-2, h-2, 3, 4, nanovgo.MONO(0, 32), nanovgo.MONO(0, 64))
+	ctx.BeginPath()
+	ctx.RoundedRect(x, y, w, h, 3)
+	ctx.SetFillPaint(bg)
+	ctx.Fill()
+
+	rows := t.visibleRows()
+	t.setScrollPosition(t.scrollPosition)
+	rowH := t.ItemHeight()
+	rowW := w
+	if t.scrollBarVisible() {
+		rowW -= scrollBarSize
+	}
+	indent := float32(t.indent)
+	fontSize := float32(t.FontSize())
+
+	ctx.Save()
+	ctx.IntersectScissor(x, y, w, h)
+	first := int(t.scrollPosition) / rowH
+	last := minI(len(rows), (int(t.scrollPosition)+t.h)/rowH+1)
+	for index := first; index < last; index++ {
+		node := rows[index]
+		rowY := y + float32(index*rowH) - t.scrollPosition
+		drawItemBackground(ctx, x, rowY, rowW, float32(rowH), node == t.selected, node == t.hover && t.enabled,
+			node == t.selected && t.focused)
+
+		depth := node.Depth()
+		if t.showGuides && depth > 0 {
+			ctx.BeginPath()
+			for level := 0; level < depth; level++ {
+				gx := floorF(x+4+float32(level)*indent+indent*0.5) + 0.5
+				ctx.MoveTo(gx, rowY)
+				ctx.LineTo(gx, rowY+float32(rowH))
+			}
+			ctx.SetStrokeWidth(1.0)
+			ctx.SetStrokeColor(t.theme.BorderLight)
+			ctx.Stroke()
+		}
+
+		textColor := t.theme.TextColor
+		if !t.enabled {
+			textColor = t.theme.DisabledTextColor
+		}
+		cx := x + float32(t.chevronX(node))
+		cy := rowY + float32(rowH)*0.5
+		ctx.SetFillColor(textColor)
+		if node.HasChildren() {
+			icon := IconRightOpen
+			if node.expanded {
+				icon = IconDownOpen
+			}
+			ctx.SetFontSize(fontSize)
+			ctx.SetFontFace(t.theme.FontIcons)
+			ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+			ctx.TextRune(cx+indent*0.5, cy, []rune{rune(icon)})
+		}
+		tx := cx + indent
+		if node.Icon != 0 {
+			ctx.SetFontSize(fontSize * 1.2)
+			ctx.SetFontFace(t.theme.FontIcons)
+			ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+			iw := ctx.TextRune(tx, cy, []rune{rune(node.Icon)})
+			tx = iw + 4
+		}
+		ctx.SetFontSize(fontSize)
+		ctx.SetFontFace(t.theme.FontNormal)
+		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+		ctx.Text(tx, cy, node.Caption)
+	}
+	ctx.Restore()
+
+	if t.scrollBarVisible() {
+		offset, size := t.thumbRect()
+		drawScrollBar(ctx, false, x+w-scrollBarSize, y+4, 8, h-8, offset, size)
+	}
+}
+
+func (t *TreeView) String() string {
+	return t.StringHelper("TreeView", fmt.Sprintf("%d rows", len(t.visibleRows())))
+}
@@ -104,6 +104,13 @@ func absF(a float32) float32 {
 	return a
 }
 
+func absI(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
 func floorF(a float32) float32 {
 	return float32(math.Floor(float64(a)))
 }
@@ -4,7 +4,6 @@ import (
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/maxfish/vg4go-gl4"
 	"runtime"
-
 )
 
 type Scroller interface {
@@ -17,10 +16,25 @@ type VScrollPanel struct {
 	childPreferredHeight int
 	scroll               float32
 	scrollPosition       float32
+
+	smooth        bool
+	kinetic       bool
+	duration      float32
+	deceleration  float32
+	animFrom      float32
+	animTarget    float32
+	animStart     float32
+	velocity      float32
+	dragging      bool
+	lastDragTime  float32
+	lastFrameTime float32
 }
 
 func NewVScrollPanel(parent Widget) *VScrollPanel {
-	panel := new(VScrollPanel)
+	panel := &VScrollPanel{
+		duration:     0.2,
+		deceleration: 2000,
+	}
 	InitWidget(panel, parent)
 	return panel
 }
@@ -35,6 +49,114 @@ func (v *VScrollPanel) Scroll() float32 {
 
 func (v *VScrollPanel) SetScroll(scroll float32) {
 	v.scroll = scroll
+	v.scrollPosition = scroll * float32(maxI(0, v.childPreferredHeight-v.h))
+	v.stopAnimation()
+}
+
+// SmoothScrolling() returns whether mouse wheel scrolling is animated
+func (v *VScrollPanel) SmoothScrolling() bool {
+	return v.smooth
+}
+
+// SetSmoothScrolling() sets whether mouse wheel scrolling is animated with an ease-out curve
+func (v *VScrollPanel) SetSmoothScrolling(flag bool) {
+	v.smooth = flag
+}
+
+// KineticScrolling() returns whether the content keeps moving after a drag is released
+func (v *VScrollPanel) KineticScrolling() bool {
+	return v.kinetic
+}
+
+// SetKineticScrolling() sets whether the content keeps moving (and slows down) after a drag is released
+func (v *VScrollPanel) SetKineticScrolling(flag bool) {
+	v.kinetic = flag
+}
+
+// ScrollDuration() returns the duration of a smooth scroll animation in seconds
+func (v *VScrollPanel) ScrollDuration() float32 {
+	return v.duration
+}
+
+// SetScrollDuration() sets the duration of a smooth scroll animation in seconds
+func (v *VScrollPanel) SetScrollDuration(duration float32) {
+	v.duration = duration
+}
+
+// Deceleration() returns the deceleration of kinetic scrolling in pixels/s²
+func (v *VScrollPanel) Deceleration() float32 {
+	return v.deceleration
+}
+
+// SetDeceleration() sets the deceleration of kinetic scrolling in pixels/s²
+func (v *VScrollPanel) SetDeceleration(deceleration float32) {
+	v.deceleration = deceleration
+}
+
+func (v *VScrollPanel) maxScrollPosition() float32 {
+	return maxF(0.0, float32(v.childPreferredHeight-v.h))
+}
+
+// setScrollPosition clamps the position to the scrollable range (no overscroll) and updates the relative scroll value
+func (v *VScrollPanel) setScrollPosition(position float32) {
+	maxPosition := v.maxScrollPosition()
+	v.scrollPosition = clampF(position, 0.0, maxPosition)
+	if maxPosition > 0 {
+		v.scroll = v.scrollPosition / maxPosition
+	} else {
+		v.scroll = 0.0
+	}
+}
+
+func (v *VScrollPanel) stopAnimation() {
+	v.animTarget = v.scrollPosition
+	v.animStart = 0
+	v.velocity = 0
+}
+
+// scrollSmoothlyTo starts (or retargets) an eased animation from the current position
+func (v *VScrollPanel) scrollSmoothlyTo(target float32) {
+	v.animFrom = v.scrollPosition
+	v.animTarget = clampF(target, 0.0, v.maxScrollPosition())
+	v.animStart = GetTime()
+	v.velocity = 0
+	RequestAnimationFrame()
+}
+
+// animate advances the smooth and kinetic scrolling animations; it is called once per frame
+func (v *VScrollPanel) animate() {
+	now := FrameTime()
+	dt := clampF(now-v.lastFrameTime, 0.0, 0.1)
+	v.lastFrameTime = now
+
+	if v.velocity != 0 && !v.dragging {
+		v.setScrollPosition(v.scrollPosition + v.velocity*dt)
+		speed := maxF(0.0, absF(v.velocity)-v.deceleration*dt)
+		if v.velocity < 0 {
+			speed = -speed
+		}
+		v.velocity = speed
+		if v.scrollPosition <= 0 || v.scrollPosition >= v.maxScrollPosition() {
+			v.velocity = 0
+		}
+		v.animTarget = v.scrollPosition
+		if v.velocity != 0 {
+			RequestAnimationFrame()
+		}
+	} else if v.animStart > 0 {
+		t := float32(1.0)
+		if v.duration > 0 {
+			t = clampF((now-v.animStart)/v.duration, 0.0, 1.0)
+		}
+		// ease-out cubic
+		e := 1 - (1-t)*(1-t)*(1-t)
+		v.setScrollPosition(v.animFrom + (v.animTarget-v.animFrom)*e)
+		if t < 1 {
+			RequestAnimationFrame()
+		} else {
+			v.animStart = 0
+		}
+	}
 }
 
 func (v *VScrollPanel) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
@@ -68,14 +190,25 @@ func (v *VScrollPanel) MouseDragEvent(self Widget, x, y, relX, relY, button int,
 		return false
 	}
 	if v.h < v.childPreferredHeight {
-		h := float32(v.h)
-		ph := float32(v.childPreferredHeight)
 		if runtime.GOOS != "darwin" {
 			relY = -relY
 		}
 		scrollAmount := float32(relY) * 2
-		v.scrollPosition = clampF(v.scrollPosition-scrollAmount, 0.0, ph-h)
-		v.scroll = clampF(v.scrollPosition/(ph-h), 0.0, 1.0)
+		v.setScrollPosition(v.scrollPosition - scrollAmount)
+		v.animStart = 0
+		v.animTarget = v.scrollPosition
+
+		now := GetTime()
+		if v.kinetic && now > v.lastDragTime {
+			speed := -scrollAmount / (now - v.lastDragTime)
+			if !v.dragging {
+				v.velocity = speed
+			} else {
+				v.velocity = v.velocity*0.2 + speed*0.8
+			}
+		}
+		v.lastDragTime = now
+		v.dragging = true
 	} else {
 		v.scroll = 0.0
 	}
@@ -84,20 +217,36 @@ func (v *VScrollPanel) MouseDragEvent(self Widget, x, y, relX, relY, button int,
 
 func (v *VScrollPanel) ScrollEvent(self Widget, x, y, relX, relY int) bool {
 	if v.h < v.childPreferredHeight {
-		h := float32(v.h)
-		ph := float32(v.childPreferredHeight)
 		scrollAmount := float32(relY) * 2
-
-		v.scrollPosition = clampF(v.scrollPosition-scrollAmount, 0.0, ph-h)
-		v.scroll = clampF(v.scrollPosition/(ph-h), 0.0, 1.0)
+		if v.smooth {
+			v.scrollSmoothlyTo(v.animTarget - scrollAmount)
+		} else {
+			v.setScrollPosition(v.scrollPosition - scrollAmount)
+			v.stopAnimation()
+		}
 	} else {
 		v.scroll = 0.0
 		v.scrollPosition = 0.0
+		v.stopAnimation()
 	}
 	return true
 }
 
 func (v *VScrollPanel) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 {
+		if down {
+			v.stopAnimation()
+		} else if v.dragging {
+			v.dragging = false
+			// the content was held still before being released
+			if GetTime()-v.lastDragTime > 0.1 {
+				v.velocity = 0
+			}
+			if v.velocity != 0 {
+				RequestAnimationFrame()
+			}
+		}
+	}
 	if len(v.children) == 0 {
 		return false
 	}
@@ -131,6 +280,7 @@ func (v *VScrollPanel) Draw(self Widget, ctx *nanovgo.Context) {
 	} else {
 		_, v.childPreferredHeight = child.PreferredSize(child, ctx)
 	}
+	v.animate()
 
 	ctx.Save()
 	//ctx.Translate(x,y)
@@ -138,7 +288,7 @@ func (v *VScrollPanel) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.Translate(0, -v.scroll*(float32(v.childPreferredHeight)-h))
 
 	if child.Visible() {
-		child.Draw(child, ctx)
+		drawWidget(child, ctx)
 	}
 	ctx.Restore()
 	if v.childPreferredHeight > v.h {
@@ -39,6 +39,16 @@ type Widget interface {
 	SetFixedWidth(w int)
 	FixedHeight() int
 	SetFixedHeight(h int)
+	MinSize() (int, int)
+	SetMinSize(w, h int)
+	MaxSize() (int, int)
+	SetMaxSize(w, h int)
+	SizePolicy() (SizePolicy, SizePolicy)
+	SetSizePolicy(horizontal, vertical SizePolicy)
+	Margin() Insets
+	SetMargin(margin Insets)
+	Padding() Insets
+	SetPadding(padding Insets)
 	Clamp() [2]bool
 	SetClampWidth(clamp bool)
 	SetClampHeight(clamp bool)
@@ -47,6 +57,15 @@ type Widget interface {
 	SetVisible(v bool)
 	VisibleRecursive() bool
 
+	Alpha() float32
+	SetAlpha(alpha float32)
+
+	RequestRedraw()
+
+	InvalidateLayout()
+	LayoutDirty() bool
+	SetLayoutDirty(dirty bool)
+
 	ChildCount() int
 	Children() []Widget
 	SetChildren([]Widget)
@@ -75,6 +94,8 @@ type Widget interface {
 
 	Cursor() Cursor
 	SetCursor(c Cursor)
+	ContextMenu() *Menu
+	SetContextMenu(menu *Menu)
 
 	Contains(x, y int) bool
 	IsClipped(x, y, w, h int) bool
@@ -104,6 +125,9 @@ type WidgetImplement struct {
 	layout                     Layout
 	theme                      *Theme
 	x, y, w, h, fixedW, fixedH int
+	minW, minH, maxW, maxH     int
+	sizePolicy                 [2]SizePolicy
+	margin, padding            Insets
 	clamp                      [2]bool
 	visible, enabled           bool
 	focused, mouseFocus        bool
@@ -111,6 +135,9 @@ type WidgetImplement struct {
 	tooltip                    string
 	fontSize                   int
 	cursor                     Cursor
+	contextMenu                *Menu
+	transparency               float32
+	layoutDirty                bool
 	children                   []Widget
 }
 
@@ -138,6 +165,7 @@ func (w *WidgetImplement) Layout() Layout {
 // SetLayout() set the used layout generator
 func (w *WidgetImplement) SetLayout(layout Layout) {
 	w.layout = layout
+	w.InvalidateLayout()
 }
 
 // Theme() returns the theme used to draw this widget
@@ -148,6 +176,7 @@ func (w *WidgetImplement) Theme() *Theme {
 // SetTheme() set the theme used to draw this widget
 func (w *WidgetImplement) SetTheme(theme *Theme) {
 	w.theme = theme
+	w.InvalidateLayout()
 }
 
 // Position() returns the position relative to the parent widget
@@ -157,8 +186,11 @@ func (w *WidgetImplement) Position() (int, int) {
 
 // SetPosition() set the position relative to the parent widget
 func (w *WidgetImplement) SetPosition(x, y int) {
-	w.x = x
-	w.y = y
+	if w.x != x || w.y != y {
+		w.x = x
+		w.y = y
+		w.RequestRedraw()
+	}
 }
 
 // AbsolutePosition() returns the absolute position on screen
@@ -182,8 +214,11 @@ func (w *WidgetImplement) Size() (int, int) {
 
 // SetSize() set the size of the widget
 func (wg *WidgetImplement) SetSize(w, h int) {
-	wg.w = w
-	wg.h = h
+	if wg.w != w || wg.h != h {
+		wg.w = w
+		wg.h = h
+		wg.RequestRedraw()
+	}
 }
 
 // Width() returns the width of the widget
@@ -193,7 +228,7 @@ func (w *WidgetImplement) Width() int {
 
 // SetWidth() set the width of the widget
 func (wg *WidgetImplement) SetWidth(w int) {
-	wg.w = w
+	wg.SetSize(w, wg.h)
 }
 
 // Height() returns the height of the widget
@@ -203,7 +238,7 @@ func (w *WidgetImplement) Height() int {
 
 // SetHeight() set the height of the widget
 func (w *WidgetImplement) SetHeight(h int) {
-	w.h = h
+	w.SetSize(w.w, h)
 }
 
 // Return the fixed size (see SetFixedSize())
@@ -220,6 +255,7 @@ func (w *WidgetImplement) FixedSize() (int, int) {
 func (wg *WidgetImplement) SetFixedSize(w, h int) {
 	wg.fixedW = w
 	wg.fixedH = h
+	wg.InvalidateLayout()
 }
 
 // FixedWidth() returns the fixed width (see SetFixedSize())
@@ -235,11 +271,76 @@ func (w *WidgetImplement) FixedHeight() int {
 // SetFixedWidth() set the fixed width (see SetFixedSize())
 func (wg *WidgetImplement) SetFixedWidth(w int) {
 	wg.fixedW = w
+	wg.InvalidateLayout()
 }
 
 // SetFixedSize() set the fixed height (see SetFixedSize())
 func (w *WidgetImplement) SetFixedHeight(h int) {
 	w.fixedH = h
+	w.InvalidateLayout()
+}
+
+// MinSize() returns the minimum size (see SetMinSize())
+func (w *WidgetImplement) MinSize() (int, int) {
+	return w.minW, w.minH
+}
+
+// SetMinSize() set the minimum size of this widget.
+// If nonzero, layout generators never make the widget smaller than this
+// on the corresponding axis. A fixed size takes precedence.
+func (wg *WidgetImplement) SetMinSize(w, h int) {
+	wg.minW = w
+	wg.minH = h
+	wg.InvalidateLayout()
+}
+
+// MaxSize() returns the maximum size (see SetMaxSize())
+func (w *WidgetImplement) MaxSize() (int, int) {
+	return w.maxW, w.maxH
+}
+
+// SetMaxSize() set the maximum size of this widget.
+// If nonzero, layout generators never make the widget larger than this
+// on the corresponding axis. A fixed size takes precedence.
+func (wg *WidgetImplement) SetMaxSize(w, h int) {
+	wg.maxW = w
+	wg.maxH = h
+	wg.InvalidateLayout()
+}
+
+// SizePolicy() returns the horizontal and vertical size policies (see SizePolicy)
+func (w *WidgetImplement) SizePolicy() (SizePolicy, SizePolicy) {
+	return w.sizePolicy[0], w.sizePolicy[1]
+}
+
+// SetSizePolicy() set how layout generators resize the widget on each axis
+func (w *WidgetImplement) SetSizePolicy(horizontal, vertical SizePolicy) {
+	w.sizePolicy = [2]SizePolicy{horizontal, vertical}
+	w.InvalidateLayout()
+}
+
+// Margin() returns the space layouts leave around the widget (see SetMargin())
+func (w *WidgetImplement) Margin() Insets {
+	return w.margin
+}
+
+// SetMargin() set the space layout generators leave around the widget,
+// in addition to the margin and spacing of the layout itself
+func (w *WidgetImplement) SetMargin(margin Insets) {
+	w.margin = margin
+	w.InvalidateLayout()
+}
+
+// Padding() returns the space between the edges of the widget and its children (see SetPadding())
+func (w *WidgetImplement) Padding() Insets {
+	return w.padding
+}
+
+// SetPadding() set the space the layout generator of this widget leaves
+// between its edges and its children, in addition to the layout margin
+func (w *WidgetImplement) SetPadding(padding Insets) {
+	w.padding = padding
+	w.InvalidateLayout()
 }
 
 // Clamp() returns whether preferred size is used as fixed size
@@ -264,7 +365,10 @@ func (w *WidgetImplement) Visible() bool {
 
 // SetVisible() set whether or not the widget is currently visible (assuming all parents are visible)
 func (w *WidgetImplement) SetVisible(v bool) {
-	w.visible = v
+	if w.visible != v {
+		w.visible = v
+		w.InvalidateLayout()
+	}
 }
 
 // VisibleRecursive() checks if this widget is currently visible, taking parent widgets into account
@@ -275,6 +379,53 @@ func (w *WidgetImplement) VisibleRecursive() bool {
 	return w.Visible()
 }
 
+// Alpha() returns the opacity the widget and its children are drawn with (1.0 by default)
+func (w *WidgetImplement) Alpha() float32 {
+	return 1.0 - w.transparency
+}
+
+// SetAlpha() sets the opacity the widget and its children are drawn with
+func (w *WidgetImplement) SetAlpha(alpha float32) {
+	w.transparency = 1.0 - clampF(alpha, 0.0, 1.0)
+	w.RequestRedraw()
+}
+
+// RequestRedraw() marks the screen containing the widget as needing to be drawn
+//
+// Widgets call it when their look changes outside of input events (which
+// always cause a redraw); animations should use RequestAnimationFrame() instead.
+func (w *WidgetImplement) RequestRedraw() {
+	if w.parent != nil {
+		w.parent.RequestRedraw()
+	}
+}
+
+// InvalidateLayout() marks the widget and its ancestors as needing a new layout
+//
+// Setters changing the preferred size call it; at the next frame the screen
+// lays out again the top level windows containing invalidated widgets, so
+// that calling Screen.PerformLayout() is not needed after such changes.
+func (w *WidgetImplement) InvalidateLayout() {
+	if w.layoutDirty {
+		// the ancestors are already marked
+		return
+	}
+	w.layoutDirty = true
+	if w.parent != nil {
+		w.parent.InvalidateLayout()
+	}
+}
+
+// LayoutDirty() returns whether the widget needs a new layout (see InvalidateLayout())
+func (w *WidgetImplement) LayoutDirty() bool {
+	return w.layoutDirty
+}
+
+// SetLayoutDirty() sets the layout flag of the widget alone; use InvalidateLayout() to request a new layout
+func (w *WidgetImplement) SetLayoutDirty(dirty bool) {
+	w.layoutDirty = dirty
+}
+
 // ChildCount() returns the number of child widgets
 func (w *WidgetImplement) ChildCount() int {
 	return len(w.children)
@@ -296,6 +447,7 @@ func (w *WidgetImplement) SetChildren(children []Widget) {
 func (w *WidgetImplement) AddChild(self, child Widget) {
 	w.children = append(w.children, child)
 	child.SetParent(self)
+	w.InvalidateLayout()
 }
 
 // RemoveChildByIndex() removes a child widget by index
@@ -311,6 +463,7 @@ func (w *WidgetImplement) RemoveChildByIndex(index int) {
 		}
 	}
 	w.children = newChildren
+	w.InvalidateLayout()
 }
 
 // RemoveChild() removes a child widget by value
@@ -349,7 +502,10 @@ func (w *WidgetImplement) Enabled() bool {
 
 /// SetEnabled() set whether or not this widget is currently enabled
 func (w *WidgetImplement) SetEnabled(e bool) {
-	w.enabled = e
+	if w.enabled != e {
+		w.enabled = e
+		w.RequestRedraw()
+	}
 }
 
 // Focused() returns whether or not this widget is currently focused
@@ -395,6 +551,7 @@ func (w *WidgetImplement) FontSize() int {
 // SetFontSize() set the font size of this widget
 func (w *WidgetImplement) SetFontSize(s int) {
 	w.fontSize = s
+	w.InvalidateLayout()
 }
 
 // HasFontSize() return whether the font size is explicitly specified for this widget
@@ -412,6 +569,16 @@ func (w *WidgetImplement) SetCursor(c Cursor) {
 	w.cursor = c
 }
 
+// ContextMenu() returns the menu opened by right-clicking the widget, or nil
+func (w *WidgetImplement) ContextMenu() *Menu {
+	return w.contextMenu
+}
+
+// SetContextMenu() sets the menu opened by right-clicking the widget (or pressing the Menu key while it has the focus)
+func (w *WidgetImplement) SetContextMenu(menu *Menu) {
+	w.contextMenu = menu
+}
+
 // Contains() checks if the widget contains a certain position
 func (w *WidgetImplement) Contains(x, y int) bool {
 	return w.x <= x && w.y <= y && x <= w.x+w.w && y <= w.y+w.h
@@ -541,11 +708,7 @@ func (w *WidgetImplement) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
 		w.layout.OnPerformLayout(self, ctx)
 	} else {
 		for _, child := range w.children {
-			prefW, prefH := child.PreferredSize(child, ctx)
-			fixW, fixH := child.FixedSize()
-			w := toI(fixW > 0, fixW, prefW)
-			h := toI(fixH > 0, fixH, prefH)
-			child.SetSize(w, h)
+			child.SetSize(LayoutSize(child, ctx))
 			child.OnPerformLayout(child, ctx)
 		}
 	}
@@ -574,7 +737,7 @@ func (w *WidgetImplement) Draw(self Widget, ctx *nanovgo.Context) {
 				cx, cy := child.Position()
 				cw, ch := child.Size()
 				if !self.IsClipped(cx, cy, cw, ch) {
-					child.Draw(child, ctx)
+					drawWidget(child, ctx)
 				}
 			} else {
 				drawLater = append(drawLater, child)
@@ -587,12 +750,27 @@ func (w *WidgetImplement) Draw(self Widget, ctx *nanovgo.Context) {
 		cx, cy := child.Position()
 		cw, ch := child.Size()
 		if !self.IsClipped(cx, cy, cw, ch) {
-			child.Draw(child, ctx)
+			drawWidget(child, ctx)
 		}
 	}
 	ctx.Translate(-float32(w.x), -float32(w.y))
 }
 
+// drawWidget draws a child widget applying its opacity
+func drawWidget(child Widget, ctx *nanovgo.Context) {
+	alpha := child.Alpha()
+	if alpha >= 1.0 {
+		child.Draw(child, ctx)
+		return
+	} else if alpha <= 0.0 {
+		return
+	}
+	ctx.Save()
+	ctx.SetGlobalAlpha(ctx.GlobalAlpha() * alpha)
+	child.Draw(child, ctx)
+	ctx.Restore()
+}
+
 func (w *WidgetImplement) String() string {
 	return w.StringHelper("Widget", "")
 }
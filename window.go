@@ -10,6 +10,7 @@ type Window struct {
 	WidgetImplement
 	title       string
 	buttonPanel Widget
+	menuBar     *MenuBar
 	modal       bool
 	drag        bool
 	draggable   bool
@@ -42,6 +43,7 @@ func (w *Window) Title() string {
 // SetTitle() sets the window title
 func (w *Window) SetTitle(title string) {
 	w.title = title
+	w.InvalidateLayout()
 }
 
 // Modal() returns is this a model dialog?
@@ -70,6 +72,25 @@ func (w *Window) ButtonPanel() Widget {
 	return w.buttonPanel
 }
 
+// MenuBar() returns the menu bar shown below the title of the window, or nil (see NewMenuBar())
+func (w *Window) MenuBar() *MenuBar {
+	return w.menuBar
+}
+
+// HeaderHeight() returns the height of the area above the content: the title bar, plus the menu bar if any.
+// It is 0 for a window without title nor menu bar.
+func (w *Window) HeaderHeight() int {
+	barVisible := w.menuBar != nil && w.menuBar.Visible()
+	if w.title == "" && !barVisible {
+		return 0
+	}
+	height := w.theme.WindowHeaderHeight
+	if barVisible {
+		height += w.menuBar.BarHeight()
+	}
+	return height
+}
+
 // Dispose() disposes the window
 func (w *Window) Dispose() {
 	var widget Widget = w
@@ -133,6 +154,10 @@ func (w *Window) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	if w.buttonPanel != nil {
 		w.buttonPanel.SetVisible(true)
 	}
+	if w.menuBar != nil && w.menuBar.Visible() {
+		barWidth, _ := w.menuBar.PreferredSize(w.menuBar, ctx)
+		width = maxI(width, barWidth)
+	}
 	ctx.SetFontSize(float32(w.theme.WindowHeaderFontSize))
 	ctx.SetFontFace(w.theme.FontBold)
 	_, bounds := ctx.TextBounds(0, 0, w.title)
@@ -141,6 +166,14 @@ func (w *Window) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 }
 
 func (w *Window) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+	// the menu bar is not placed by the layout (see MenuBar.IsPositionAbsolute()): it spans the window below the title
+	if w.menuBar != nil && w.menuBar.Visible() {
+		defer func() {
+			w.menuBar.SetPosition(0, w.theme.WindowHeaderHeight)
+			w.menuBar.SetSize(w.w, w.menuBar.BarHeight())
+			w.menuBar.OnPerformLayout(w.menuBar, ctx)
+		}()
+	}
 	if w.buttonPanel == nil {
 		w.WidgetImplement.OnPerformLayout(self, ctx)
 	} else {